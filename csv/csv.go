@@ -1,10 +1,19 @@
 package csv
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/devify-me/devify-utils/fileio"
 )
@@ -12,8 +21,10 @@ import (
 // ReadFile reads a CSV file from the specified path and stores the records in the provided destination.
 //
 // The destination must be a pointer to a slice of string slices (*[][]string). The function validates the file path,
-// ensures it has a .csv extension, and checks that the file is not empty. If any errors occur during reading or if the
-// destination type is incorrect, an error is returned.
+// ensures it has a .csv extension, and checks that the file is not empty. The delimiter and comment character can be
+// configured with opts (e.g., Options{Delimiter: ';'} for semicolon-separated exports). A leading UTF-8 byte order
+// mark, as written by Excel, is stripped. If any errors occur during reading or if the destination type is
+// incorrect, an error is returned.
 //
 // Example:
 //
@@ -27,10 +38,12 @@ import (
 // Parameters:
 //   - path: The file path of the CSV file to read.
 //   - dest: A pointer to a slice of string slices (*[][]string) where the CSV records will be stored.
+//   - opts: Optional Options setting the delimiter and comment character. Defaults to DefaultOptions() if not provided.
 //
 // Returns:
-//   - error: An error if the file cannot be read, the path is invalid, the file is empty, or the destination type is incorrect.
-func ReadFile(path string, dest any) error {
+//   - error: An error if the file cannot be read, the path is invalid, the file is empty, the options are invalid,
+//     or the destination type is incorrect.
+func ReadFile(path string, dest any, opts ...Options) error {
 	if err := fileio.ValidateReadPath(path, ".csv"); err != nil {
 		return err
 	}
@@ -39,7 +52,10 @@ func ReadFile(path string, dest any) error {
 		return err
 	}
 	defer file.Close()
-	reader := csv.NewReader(file)
+	reader, err := newReader(file, opts)
+	if err != nil {
+		return err
+	}
 	records, err := reader.ReadAll()
 	if err != nil {
 		return err
@@ -55,6 +71,302 @@ func ReadFile(path string, dest any) error {
 	return nil
 }
 
+// StreamFile reads a CSV file from the specified path one record at a time, calling fn for each record.
+//
+// Unlike ReadFile, the file is never loaded into memory as a whole, so arbitrarily large files can be processed
+// (e.g., to compute aggregates). Records are passed to fn in file order, including any header row. Each record
+// slice is newly allocated and may be retained by fn. If fn returns an error, reading stops immediately and that
+// error is returned unchanged. The path is validated as in ReadFile, and the delimiter and comment character can
+// be configured with opts.
+//
+// Example:
+//
+//	var total float64
+//	err := StreamFile("sales.csv", func(record []string) error {
+//	    amount, err := strconv.ParseFloat(record[2], 64)
+//	    if err != nil {
+//	        return nil // Skip the header and malformed amounts
+//	    }
+//	    total += amount
+//	    return nil
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: The file path of the CSV file to read.
+//   - fn: The function called with each record.
+//   - opts: Optional Options setting the delimiter and comment character. Defaults to DefaultOptions() if not provided.
+//
+// Returns:
+//   - error: An error if the path or options are invalid, fn is nil, the file is empty or malformed, or fn returns an error.
+func StreamFile(path string, fn func(record []string) error, opts ...Options) error {
+	if fn == nil {
+		return errors.New("callback cannot be nil")
+	}
+	if err := fileio.ValidateReadPath(path, ".csv"); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := newReader(file, opts)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		count++
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if count == 0 {
+		return errors.New("file is empty")
+	}
+	return nil
+}
+
+// Options configures how CSV files are parsed and written by functions such as ReadFile, WriteFileWithOptions,
+// and ReadFileToMaps.
+//
+// The zero value of Options has no header rows, a comma delimiter, and no comment character; use DefaultOptions
+// as a starting point to keep the default behavior while customizing individual fields. In particular, a partially
+// filled literal such as Options{Delimiter: ';'} has HeaderRows 0, so ReadFileToMaps and TransformFile treat its
+// first row as data:
+//
+//	opts := DefaultOptions()
+//	opts.Delimiter = ';' // Semicolon-separated, first row is still the header
+type Options struct {
+	// HeaderRows is the number of leading rows used to determine column names. When greater than 1, the
+	// non-empty cells of each column are joined with "." into a composite name (e.g., "Q1.Revenue").
+	// When 0, columns are named by their zero-based index ("0", "1", ...).
+	HeaderRows int
+	// Delimiter is the field separator (e.g., ';' or '\t'). When 0, a comma is used. It must not be a quote,
+	// carriage return, newline, or the Unicode replacement character.
+	Delimiter rune
+	// Comment, if not 0, marks lines starting with it as comments to skip when reading. It is ignored when writing
+	// and must differ from Delimiter.
+	Comment rune
+}
+
+// DefaultOptions returns the Options used when none are provided, with a single header row and a comma delimiter.
+//
+// Returns:
+//   - Options: The default options (HeaderRows: 1, Delimiter: ',').
+func DefaultOptions() Options {
+	return Options{HeaderRows: 1, Delimiter: ','}
+}
+
+// validate checks that the delimiter and comment characters can be used by encoding/csv.
+func (o Options) validate() error {
+	delimiter := o.delimiter()
+	if !validDelimiter(delimiter) {
+		return fmt.Errorf("invalid delimiter %q", delimiter)
+	}
+	if o.Comment != 0 && (!validDelimiter(o.Comment) || o.Comment == delimiter) {
+		return fmt.Errorf("invalid comment character %q", o.Comment)
+	}
+	return nil
+}
+
+// delimiter returns the configured delimiter, defaulting to a comma.
+func (o Options) delimiter() rune {
+	if o.Delimiter == 0 {
+		return ','
+	}
+	return o.Delimiter
+}
+
+// validDelimiter reports whether r can be used as a field delimiter or comment character.
+func validDelimiter(r rune) bool {
+	return r != '"' && r != '\r' && r != '\n' && r != utf8.RuneError && utf8.ValidRune(r)
+}
+
+// utf8BOM is the UTF-8 encoding of the byte order mark U+FEFF.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// newReader returns a csv.Reader for r configured with the delimiter and comment character of opts.
+// A leading UTF-8 byte order mark, as written by Excel, is skipped so that it does not end up in the first cell.
+func newReader(r io.Reader, opts []Options) (*csv.Reader, error) {
+	options := DefaultOptions()
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	reader := csv.NewReader(buffered)
+	reader.Comma = options.delimiter()
+	reader.Comment = options.Comment
+	return reader, nil
+}
+
+// newWriter returns a csv.Writer for w configured with the delimiter of opts.
+func newWriter(w io.Writer, opts []Options) (*csv.Writer, error) {
+	options := DefaultOptions()
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = options.delimiter()
+	return writer, nil
+}
+
+// ReadFileToMaps reads a CSV file from the specified path and returns each data row as a map keyed by column name.
+//
+// Column names are determined from the leading header rows as configured by opts (see Options.HeaderRows); if opts is
+// not provided, DefaultOptions is used and the first row is the header. Custom opts should start from DefaultOptions
+// to keep that header, since HeaderRows 0 means the file has none. The function validates the file path,
+// ensures it has a .csv extension, and checks that the file is not empty. If a column name appears more than once,
+// the value from the rightmost column is kept.
+//
+// Example:
+//
+//	rows, err := ReadFileToMaps("users.csv")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(rows[0]["name"]) // Prints the name column of the first data row
+//
+// Parameters:
+//   - path: The file path of the CSV file to read.
+//   - opts: Optional Options controlling header handling and parsing. Defaults to DefaultOptions() if not provided.
+//
+// Returns:
+//   - []map[string]string: The data rows keyed by column name, in file order.
+//   - error: An error if the path is invalid, the file is empty or malformed, HeaderRows is negative,
+//     or the file has fewer rows than HeaderRows.
+func ReadFileToMaps(path string, opts ...Options) ([]map[string]string, error) {
+	options := DefaultOptions()
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.HeaderRows < 0 {
+		return nil, fmt.Errorf("header rows must be non-negative, got %d", options.HeaderRows)
+	}
+	var records [][]string
+	if err := ReadFile(path, &records, options); err != nil {
+		return nil, err
+	}
+	if len(records) < options.HeaderRows {
+		return nil, fmt.Errorf("file has %d rows, fewer than %d header rows", len(records), options.HeaderRows)
+	}
+	columns := columnNames(records, options.HeaderRows)
+	rows := make([]map[string]string, 0, len(records)-options.HeaderRows)
+	for _, record := range records[options.HeaderRows:] {
+		row := make(map[string]string, len(record))
+		for i, value := range record {
+			row[columns[i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ReadRecords reads a CSV file from the specified path and returns each data row as a map keyed by the header row.
+//
+// It is a strict counterpart to ReadFileToMaps: the first row is always the header, and instead of silently keeping
+// the rightmost of duplicate column names, a header that appears more than once is an error. Every data row must
+// have the same number of columns as the header; otherwise the error names the row (counting the header as row 1).
+// The function validates the file path, ensures it has a .csv extension, and checks that the file is not empty.
+//
+// Example:
+//
+//	rows, err := ReadRecords("users.csv")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, row := range rows {
+//	    fmt.Println(row["name"], row["email"]) // Prints the name and email columns of each row
+//	}
+//
+// Parameters:
+//   - path: The file path of the CSV file to read.
+//
+// Returns:
+//   - []map[string]string: The data rows keyed by header name, in file order.
+//   - error: An error if the path is invalid, the file is empty or malformed, a header name is duplicated,
+//     or a row has the wrong number of columns.
+func ReadRecords(path string) ([]map[string]string, error) {
+	if err := fileio.ValidateReadPath(path, ".csv"); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := newReader(file, nil)
+	if err != nil {
+		return nil, err
+	}
+	reader.FieldsPerRecord = -1 // Column counts are checked below to report the row number
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	headers := records[0]
+	seen := make(map[string]int, len(headers))
+	for i, header := range headers {
+		if j, ok := seen[header]; ok {
+			return nil, fmt.Errorf("duplicate header %q in columns %d and %d", header, j+1, i+1)
+		}
+		seen[header] = i
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != len(headers) {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", i+2, len(record), len(headers))
+		}
+		row := make(map[string]string, len(record))
+		for j, value := range record {
+			row[headers[j]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// columnNames derives a name for each column from the first headerRows records.
+func columnNames(records [][]string, headerRows int) []string {
+	names := make([]string, len(records[0]))
+	for i := range names {
+		var parts []string
+		for _, header := range records[:headerRows] {
+			if part := strings.TrimSpace(header[i]); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) == 0 {
+			names[i] = strconv.Itoa(i)
+			continue
+		}
+		names[i] = strings.Join(parts, ".")
+	}
+	return names
+}
+
 // WriteFile writes a slice of string slices to a CSV file at the specified path.
 //
 // The data must be a slice of string slices ([][]string) and must not be empty. The function validates the file path,
@@ -77,9 +389,38 @@ func ReadFile(path string, dest any) error {
 // Returns:
 //   - error: An error if the path is invalid, data is empty or of incorrect type, directory creation fails, or writing fails.
 func WriteFile(data any, path string, perm ...os.FileMode) error {
+	return WriteFileWithOptions(data, path, DefaultOptions(), perm...)
+}
+
+// WriteFileWithOptions writes a slice of string slices to a CSV file at the specified path using the given Options.
+//
+// It behaves like WriteFile, but separates fields with opts.Delimiter (a comma if 0), for example to produce
+// tab-separated or semicolon-separated files. The options are validated before the file is created.
+//
+// Example:
+//
+//	records := [][]string{{"name", "city"}, {"Zoë", "Köln"}}
+//	err := WriteFileWithOptions(records, "export.csv", Options{Delimiter: ';'}, 0o644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - data: The CSV data to write, as a slice of string slices ([][]string).
+//   - path: The file path where the CSV file will be written.
+//   - opts: The Options setting the delimiter.
+//   - perm: Optional file permission mode (os.FileMode). Defaults to 0600 if not provided.
+//
+// Returns:
+//   - error: An error if the path or options are invalid, data is empty or of incorrect type, directory creation fails,
+//     or writing fails.
+func WriteFileWithOptions(data any, path string, opts Options, perm ...os.FileMode) error {
 	if err := fileio.ValidateWritePath(path, ".csv"); err != nil {
 		return err
 	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
 	records, ok := data.([][]string)
 	if !ok {
 		return errors.New("data must be [][]string")
@@ -99,18 +440,293 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 		return err
 	}
 	defer file.Close()
-	writer := csv.NewWriter(file)
+	return WriteTo(file, records, opts)
+}
+
+// AppendFile appends CSV records to the file at the specified path, creating it if it does not exist.
+//
+// Unlike WriteFile, the existing content is kept and the records are written after it, so rows can be added
+// incrementally (e.g., to a CSV log). No header is written: include one in the first call if needed. If the existing
+// file does not end with a newline, one is added before the new records so they start on their own line. The path
+// is validated as in WriteFile and any necessary parent directories are created. A file permission mode can be
+// optionally provided for a newly created file; otherwise, a default mode of 0600 is used.
+//
+// Example:
+//
+//	err := AppendFile([][]string{{time.Now().Format(time.RFC3339), "login", "alice"}}, "audit.csv")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - data: The CSV records to append.
+//   - path: The file path of the CSV file to append to.
+//   - perm: Optional file permission mode (os.FileMode) used if the file is created. Defaults to 0600 if not provided.
+//
+// Returns:
+//   - error: An error if the path is invalid, data is empty, directory creation fails, or writing fails.
+func AppendFile(data [][]string, path string, perm ...os.FileMode) error {
+	if err := fileio.ValidateWritePath(path, ".csv"); err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("records cannot be empty")
+	}
+	if err := fileio.EnsureDir(path, 0o755); err != nil {
+		return err
+	}
+	fileMode := os.FileMode(0o600)
+	if len(perm) > 0 {
+		fileMode = perm[0]
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, fileMode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if size := info.Size(); size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			if _, err := file.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
+	}
+	return WriteTo(file, data)
+}
+
+// WriteTo writes CSV records to w and flushes them, without staging the data in a file.
+//
+// This allows CSV to be streamed directly to destinations such as an http.ResponseWriter, a gzip.Writer, or a
+// network connection. The delimiter can be configured with opts. Empty records write nothing. The first error from
+// encoding or from w is returned.
+//
+// Example:
+//
+//	func export(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "text/csv")
+//	    if err := WriteTo(w, [][]string{{"id", "name"}, {"1", "Alice"}}); err != nil {
+//	        log.Println(err)
+//	    }
+//	}
+//
+// Parameters:
+//   - w: The writer to write the CSV data to.
+//   - records: The CSV records to write.
+//   - opts: Optional Options setting the delimiter. Defaults to DefaultOptions() if not provided.
+//
+// Returns:
+//   - error: An error if w is nil, the options are invalid, or writing fails.
+func WriteTo(w io.Writer, records [][]string, opts ...Options) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	writer, err := newWriter(w, opts)
+	if err != nil {
+		return err
+	}
 	if err := writer.WriteAll(records); err != nil {
 		return err
 	}
+	return writer.Error()
+}
+
+// TransformFile streams the records of the CSV file at srcPath through fn and writes the kept rows to dstPath.
+//
+// Records are read and written one at a time, so files larger than memory can be processed. For each data row, fn
+// returns the transformed row, whether to keep it, and an error; processing stops at the first error from fn, and
+// that error is returned. The first HeaderRows records (1 by default, and 0 for Options{} literals; see Options) are
+// copied to dstPath unchanged without calling fn. Both paths must have a .csv extension and must differ; parent
+// directories of dstPath are created if needed, and dstPath is written with mode 0600. If the transform fails, the
+// partially written dstPath is removed.
+//
+// Example:
+//
+//	err := TransformFile("in.csv", "out.csv", func(row []string) ([]string, bool, error) {
+//	    if row[1] == "" {
+//	        return nil, false, nil // Drop rows without an email
+//	    }
+//	    row[1] = strings.ToLower(row[1])
+//	    return row, true, nil
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - srcPath: The file path of the CSV file to read.
+//   - dstPath: The file path where the transformed CSV file will be written.
+//   - fn: The function applied to each data row, returning the new row, a keep flag, and an error.
+//   - opts: Optional Options controlling how many header rows are passed through and the delimiter and comment
+//     character used for both files. Defaults to DefaultOptions().
+//
+// Returns:
+//   - error: An error if either path is invalid, the paths are the same, HeaderRows is negative, the source is
+//     malformed, fn returns an error, or writing fails.
+func TransformFile(srcPath, dstPath string, fn func(row []string) ([]string, bool, error), opts ...Options) (err error) {
+	options := DefaultOptions()
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.HeaderRows < 0 {
+		return fmt.Errorf("header rows must be non-negative, got %d", options.HeaderRows)
+	}
+	if err := options.validate(); err != nil {
+		return err
+	}
+	if fn == nil {
+		return errors.New("transform function cannot be nil")
+	}
+	if err := fileio.ValidateReadPath(srcPath, ".csv"); err != nil {
+		return err
+	}
+	if err := fileio.ValidateWritePath(dstPath, ".csv"); err != nil {
+		return err
+	}
+	srcAbs, err := filepath.Abs(srcPath)
+	if err != nil {
+		return err
+	}
+	dstAbs, err := filepath.Abs(dstPath)
+	if err != nil {
+		return err
+	}
+	if srcAbs == dstAbs {
+		return errors.New("source and destination must be different files")
+	}
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if err := fileio.EnsureDir(dstPath, 0o755); err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := dst.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(dstPath)
+		}
+	}()
+
+	reader, err := newReader(src, []Options{options})
+	if err != nil {
+		return err
+	}
+	writer, err := newWriter(dst, []Options{options})
+	if err != nil {
+		return err
+	}
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if line < options.HeaderRows {
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			continue
+		}
+		row, keep, err := fn(record)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			continue
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
 	writer.Flush()
 	return writer.Error()
 }
 
+// Distinct removes exact duplicate rows from CSV records, preserving the order of first occurrence.
+//
+// If keepHeader is true, the first record is treated as a header: it is always kept in first position
+// and is not compared against the data rows. The input slice is not modified; a new slice is returned.
+// Combine with Sort to produce a clean, unique, and ordered dataset.
+//
+// Example:
+//
+//	records := [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}, {"Bob", "25"}}
+//	unique := Distinct(records, true)
+//	fmt.Println(unique) // Prints [[name age] [Bob 25] [Alice 30]]
+//
+// Parameters:
+//   - records: The CSV records to deduplicate.
+//   - keepHeader: If true, the first record is kept as a header and excluded from deduplication.
+//
+// Returns:
+//   - [][]string: The records with duplicate rows removed.
+func Distinct(records [][]string, keepHeader bool) [][]string {
+	result := make([][]string, 0, len(records))
+	if keepHeader && len(records) > 0 {
+		result = append(result, records[0])
+		records = records[1:]
+	}
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		key := fmt.Sprintf("%q", record)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, record)
+	}
+	return result
+}
+
+// Sort orders CSV records lexicographically, comparing rows field by field.
+//
+// If keepHeader is true, the first record is treated as a header and kept in first position. The sort is stable,
+// and the input slice is not modified; a new slice is returned.
+//
+// Example:
+//
+//	records := [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}}
+//	sorted := Sort(Distinct(records, true), true)
+//	fmt.Println(sorted) // Prints [[name age] [Alice 30] [Bob 25]]
+//
+// Parameters:
+//   - records: The CSV records to sort.
+//   - keepHeader: If true, the first record is kept as a header and excluded from sorting.
+//
+// Returns:
+//   - [][]string: The sorted records.
+func Sort(records [][]string, keepHeader bool) [][]string {
+	result := slices.Clone(records)
+	rows := result
+	if keepHeader && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	slices.SortStableFunc(rows, slices.Compare[[]string])
+	return result
+}
+
 // Marshal converts a slice of string slices to CSV-encoded bytes.
 //
 // The input data must be a slice of string slices ([][]string) and must not be empty. The function serializes the data
-// into CSV format and returns the resulting bytes. If any errors occur during serialization, an error is returned.
+// into CSV format, separating fields with the delimiter from opts (a comma by default), and returns the resulting bytes.
+// If any errors occur during serialization, an error is returned.
 //
 // Example:
 //
@@ -123,11 +739,12 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 //
 // Parameters:
 //   - data: The CSV data to marshal, as a slice of string slices ([][]string).
+//   - opts: Optional Options setting the delimiter. Defaults to DefaultOptions() if not provided.
 //
 // Returns:
 //   - []byte: The CSV-encoded data as bytes.
-//   - error: An error if the data is empty, of incorrect type, or if serialization fails.
-func Marshal(data any) ([]byte, error) {
+//   - error: An error if the data is empty, of incorrect type, the options are invalid, or if serialization fails.
+func Marshal(data any, opts ...Options) ([]byte, error) {
 	records, ok := data.([][]string)
 	if !ok {
 		return nil, errors.New("data must be [][]string")
@@ -136,7 +753,10 @@ func Marshal(data any) ([]byte, error) {
 		return nil, errors.New("records cannot be empty")
 	}
 	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
+	writer, err := newWriter(&buf, opts)
+	if err != nil {
+		return nil, err
+	}
 	if err := writer.WriteAll(records); err != nil {
 		return nil, err
 	}
@@ -147,11 +767,165 @@ func Marshal(data any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// MarshalStructs converts a slice of structs to CSV-encoded bytes, with a header row derived from the struct fields.
+//
+// The input must be a slice or array of structs (or pointers to structs). Each exported field becomes a column, in
+// declaration order, named by its `csv:"name"` tag or, if untagged, by the field name; fields tagged `csv:"-"` are
+// skipped. Field values are formatted with strconv: strings as-is, integers in base 10, floats in the shortest
+// representation ('g' format), and booleans as "true" or "false". Any other field kind, a nil element, or a non-slice
+// input results in an error. An empty slice produces only the header row.
+//
+// Example:
+//
+//	type User struct {
+//	    Name  string  `csv:"name"`
+//	    Age   int     `csv:"age"`
+//	    Token string  `csv:"-"`
+//	}
+//	data, err := MarshalStructs([]User{{Name: "Alice", Age: 30}})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(data)) // Prints "name,age\nAlice,30\n"
+//
+// Parameters:
+//   - v: The slice or array of structs to marshal.
+//
+// Returns:
+//   - []byte: The CSV-encoded data as bytes, starting with the header row.
+//   - error: An error if v is not a slice of structs, an element is nil, a field kind is unsupported, or serialization fails.
+func MarshalStructs(v any) ([]byte, error) {
+	var records [][]string
+	err := encodeStructs(v, func(record []string) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(records)
+}
+
+// WriteStructsTo writes a slice of structs to w as CSV, with a header row derived from the struct fields.
+//
+// Fields are mapped to columns and formatted exactly as in MarshalStructs, but each row is written to w as soon as
+// it is encoded, and the output is flushed at the end. Rows written before an encoding error has occurred may
+// already have reached w. The delimiter can be configured with opts.
+//
+// Example:
+//
+//	gz := gzip.NewWriter(w)
+//	defer gz.Close()
+//	if err := WriteStructsTo(gz, users); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - w: The writer to write the CSV data to.
+//   - v: The slice or array of structs to write.
+//   - opts: Optional Options setting the delimiter. Defaults to DefaultOptions() if not provided.
+//
+// Returns:
+//   - error: An error if w is nil, the options are invalid, v is not a slice of structs, an element is nil,
+//     a field kind is unsupported, or writing fails.
+func WriteStructsTo(w io.Writer, v any, opts ...Options) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	writer, err := newWriter(w, opts)
+	if err != nil {
+		return err
+	}
+	if err := encodeStructs(v, writer.Write); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// encodeStructs converts a slice of structs to CSV records, passing the header and then each row to emit.
+func encodeStructs(v any, emit func(record []string) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("data must be a slice of structs, got %T", v)
+	}
+	elemType := rv.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("data must be a slice of structs, got %T", v)
+	}
+	var header []string
+	var fields []int
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	if err := emit(header); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		if isPtr {
+			if elem.IsNil() {
+				return fmt.Errorf("element %d is nil", i)
+			}
+			elem = elem.Elem()
+		}
+		record := make([]string, len(fields))
+		for j, index := range fields {
+			value, err := formatField(elem.Field(index))
+			if err != nil {
+				return fmt.Errorf("field %s: %w", elemType.Field(index).Name, err)
+			}
+			record[j] = value
+		}
+		if err := emit(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatField formats a basic-kind struct field value as a CSV cell.
+func formatField(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	default:
+		return "", fmt.Errorf("unsupported field kind %s", v.Kind())
+	}
+}
+
 // Unmarshal parses CSV-encoded bytes into a slice of string slices.
 //
 // The destination must be a pointer to a slice of string slices (*[][]string). The function parses the input bytes as CSV
-// data and stores the records in the provided destination. If the input data is empty, the destination type is incorrect,
-// or parsing fails, an error is returned.
+// data, using the delimiter and comment character from opts (a comma and none by default), and stores the records in the
+// provided destination. A leading UTF-8 byte order mark is stripped. If the input data is empty, the destination type
+// is incorrect, or parsing fails, an error is returned.
 //
 // Example:
 //
@@ -166,10 +940,12 @@ func Marshal(data any) ([]byte, error) {
 // Parameters:
 //   - data: The CSV-encoded data as bytes.
 //   - dest: A pointer to a slice of string slices (*[][]string) where the parsed records will be stored.
+//   - opts: Optional Options setting the delimiter and comment character. Defaults to DefaultOptions() if not provided.
 //
 // Returns:
-//   - error: An error if the data is empty, the destination type is incorrect, no records are found, or parsing fails.
-func Unmarshal(data []byte, dest any) error {
+//   - error: An error if the data is empty, the destination type is incorrect, the options are invalid, no records are
+//     found, or parsing fails.
+func Unmarshal(data []byte, dest any, opts ...Options) error {
 	if len(data) == 0 {
 		return errors.New("CSV data cannot be empty")
 	}
@@ -177,7 +953,10 @@ func Unmarshal(data []byte, dest any) error {
 	if !ok {
 		return errors.New("destination must be *[][]string")
 	}
-	reader := csv.NewReader(bytes.NewReader(data))
+	reader, err := newReader(bytes.NewReader(data), opts)
+	if err != nil {
+		return err
+	}
 	records, err := reader.ReadAll()
 	if err != nil {
 		return err
@@ -188,3 +967,163 @@ func Unmarshal(data []byte, dest any) error {
 	*recordsPtr = records
 	return nil
 }
+
+// UnmarshalStructs parses CSV-encoded bytes into a slice of structs, matching columns to fields by header name.
+//
+// The destination must be a pointer to a slice of structs (or of pointers to structs). The first CSV row is the
+// header; each column is matched to the exported field with the same `csv:"name"` tag or, if untagged, the same
+// field name, as produced by MarshalStructs. Fields tagged `csv:"-"` are never set. Columns without a matching field
+// are ignored, and fields without a matching column keep their zero value. Cells are converted with strconv into
+// string, integer, float, and bool fields; an empty cell leaves a non-string field at its zero value. Conversion
+// errors name the 1-based row (the header is row 1) and the column, e.g., "row 3 column age: cannot parse "x" as int".
+//
+// Example:
+//
+//	type User struct {
+//	    Name string `csv:"name"`
+//	    Age  int    `csv:"age"`
+//	}
+//	var users []User
+//	err := UnmarshalStructs([]byte("name,age\nAlice,30\n"), &users)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(users) // Prints [{Alice 30}]
+//
+// Parameters:
+//   - data: The CSV-encoded data as bytes, starting with a header row.
+//   - dest: A pointer to a slice of structs where the parsed records will be stored.
+//
+// Returns:
+//   - error: An error if the data is empty or malformed, dest is not a pointer to a slice of structs, a matched field
+//     has an unsupported kind, or a cell cannot be converted to its field's type.
+func UnmarshalStructs(data []byte, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("destination must be a pointer to a slice of structs")
+	}
+	sliceValue := rv.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("destination must be a pointer to a slice of structs")
+	}
+	var records [][]string
+	if err := Unmarshal(data, &records); err != nil {
+		return err
+	}
+	fieldsByName := make(map[string]int)
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldsByName[name] = i
+	}
+	header := records[0]
+	columns := make([]int, len(header)) // field index per column, or -1
+	for col, name := range header {
+		columns[col] = -1
+		if index, ok := fieldsByName[strings.TrimSpace(name)]; ok {
+			columns[col] = index
+		}
+	}
+	result := reflect.MakeSlice(sliceValue.Type(), 0, len(records)-1)
+	for row, record := range records[1:] {
+		elem := reflect.New(elemType).Elem()
+		for col, cell := range record {
+			if col >= len(columns) || columns[col] < 0 {
+				continue
+			}
+			if err := parseField(elem.Field(columns[col]), cell); err != nil {
+				return fmt.Errorf("row %d column %s: %w", row+2, header[col], err)
+			}
+		}
+		if isPtr {
+			elem = elem.Addr()
+		}
+		result = reflect.Append(result, elem)
+	}
+	sliceValue.Set(result)
+	return nil
+}
+
+// parseField converts a CSV cell into a basic-kind struct field value.
+func parseField(v reflect.Value, cell string) error {
+	if cell == "" && v.Kind() != reflect.String {
+		return nil
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(strings.TrimSpace(cell), 10, v.Type().Bits()); err == nil {
+			v.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(strings.TrimSpace(cell), 10, v.Type().Bits()); err == nil {
+			v.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(strings.TrimSpace(cell), v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+			return nil
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(strings.TrimSpace(cell)); err == nil {
+			v.SetBool(b)
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported field kind %s", v.Kind())
+	}
+	return fmt.Errorf("cannot parse %q as %s", cell, v.Kind())
+}
+
+// Serializer implements fileio.Serializer for CSV using the functions of this package.
+//
+// It is registered with fileio.Register for the ".csv" extension when this package is imported, so that
+// fileio.ReadAuto can read CSV files.
+type Serializer struct{}
+
+// Marshal serializes the given data to CSV using Marshal.
+func (Serializer) Marshal(data any) ([]byte, error) {
+	return Marshal(data)
+}
+
+// Unmarshal parses CSV data into the provided destination using Unmarshal.
+func (Serializer) Unmarshal(data []byte, dest any) error {
+	return Unmarshal(data, dest)
+}
+
+// ReadFile reads a CSV file into the provided destination using ReadFile.
+func (Serializer) ReadFile(path string, dest any) error {
+	return ReadFile(path, dest)
+}
+
+// WriteFile writes the given data to a CSV file using WriteFile.
+func (Serializer) WriteFile(data any, path string, perm ...os.FileMode) error {
+	return WriteFile(data, path, perm...)
+}
+
+func init() {
+	fileio.Register(".csv", Serializer{})
+}
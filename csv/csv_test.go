@@ -1,11 +1,17 @@
 package csv_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/devify-me/devify-utils/csv"
 )
@@ -139,6 +145,90 @@ func TestReadFile(t *testing.T) {
 	}
 }
 
+func TestStreamFile(t *testing.T) {
+	tempDir := t.TempDir()
+	largePath := filepath.Join(tempDir, "large.csv")
+	var b strings.Builder
+	b.WriteString("id,amount\n")
+	for i := 1; i <= 10000; i++ {
+		b.WriteString(strconv.Itoa(i) + "," + strconv.Itoa(i*2) + "\n")
+	}
+	os.WriteFile(largePath, []byte(b.String()), 0600)
+	tsvPath := filepath.Join(tempDir, "tabs.csv")
+	os.WriteFile(tsvPath, []byte("a\tb\n"), 0600)
+	emptyPath := filepath.Join(tempDir, "empty.csv")
+	os.WriteFile(emptyPath, []byte{}, 0600)
+	malformedPath := filepath.Join(tempDir, "malformed.csv")
+	os.WriteFile(malformedPath, []byte("a,b\nc,\"d\n"), 0600)
+	txtPath := filepath.Join(tempDir, "data.txt")
+	os.WriteFile(txtPath, []byte("a,b\n"), 0600)
+
+	t.Run("Aggregates every record", func(t *testing.T) {
+		rows, sum := 0, 0
+		err := csv.StreamFile(largePath, func(record []string) error {
+			rows++
+			if n, err := strconv.Atoi(record[1]); err == nil {
+				sum += n
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamFile() unexpected error = %v", err)
+		}
+		if rows != 10001 || sum != 10000*10001 {
+			t.Errorf("StreamFile() rows = %d, sum = %d, want 10001, %d", rows, sum, 10000*10001)
+		}
+	})
+
+	t.Run("Stops on callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		rows := 0
+		err := csv.StreamFile(largePath, func(record []string) error {
+			rows++
+			if rows == 5 {
+				return errStop
+			}
+			return nil
+		})
+		if err != errStop || rows != 5 {
+			t.Errorf("StreamFile() error = %v after %d rows, want stop after 5", err, rows)
+		}
+	})
+
+	t.Run("Delimiter option", func(t *testing.T) {
+		var got [][]string
+		err := csv.StreamFile(tsvPath, func(record []string) error {
+			got = append(got, record)
+			return nil
+		}, csv.Options{Delimiter: '\t'})
+		if err != nil || !reflect.DeepEqual(got, [][]string{{"a", "b"}}) {
+			t.Errorf("StreamFile() = %v, %v, want [[a b]]", got, err)
+		}
+	})
+
+	noop := func(record []string) error { return nil }
+	errTests := []struct {
+		name    string
+		path    string
+		fn      func([]string) error
+		wantErr string
+	}{
+		{"Nil callback", largePath, nil, "callback cannot be nil"},
+		{"File not exist", filepath.Join(tempDir, "missing.csv"), noop, "file does not exist"},
+		{"Invalid extension", txtPath, noop, "file must have .csv extension"},
+		{"Empty file", emptyPath, noop, "file is empty"},
+		{"Malformed file", malformedPath, noop, "extraneous or missing \" in quoted-field"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.StreamFile(tt.path, tt.fn)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("StreamFile() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	tempDir := t.TempDir()
 	validPath := filepath.Join(tempDir, "test.csv")
@@ -247,6 +337,277 @@ func TestWriteFile(t *testing.T) {
 }
 
 // Existing tests for Marshal and Unmarshal remain unchanged
+func TestDelimiterOptions(t *testing.T) {
+	tempDir := t.TempDir()
+	records := [][]string{{"name", "city"}, {"Zoë", "Köln; Nord"}, {"Ann", "a,b"}}
+
+	t.Run("TSV file round trip", func(t *testing.T) {
+		path := filepath.Join(tempDir, "data.csv")
+		if err := csv.WriteFileWithOptions(records, path, csv.Options{Delimiter: '\t'}); err != nil {
+			t.Fatalf("WriteFileWithOptions() unexpected error = %v", err)
+		}
+		raw, _ := os.ReadFile(path)
+		if want := "name\tcity\nZoë\tKöln; Nord\nAnn\ta,b\n"; string(raw) != want {
+			t.Errorf("WriteFileWithOptions() wrote %q, want %q", raw, want)
+		}
+		var got [][]string
+		if err := csv.ReadFile(path, &got, csv.Options{Delimiter: '\t'}); err != nil {
+			t.Fatalf("ReadFile() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(got, records) {
+			t.Errorf("ReadFile() = %v, want %v", got, records)
+		}
+	})
+
+	t.Run("Semicolon Marshal and Unmarshal", func(t *testing.T) {
+		data, err := csv.Marshal(records, csv.Options{Delimiter: ';'})
+		if err != nil {
+			t.Fatalf("Marshal() unexpected error = %v", err)
+		}
+		if want := "name;city\nZoë;\"Köln; Nord\"\nAnn;a,b\n"; string(data) != want {
+			t.Errorf("Marshal() = %q, want %q", data, want)
+		}
+		var got [][]string
+		if err := csv.Unmarshal(data, &got, csv.Options{Delimiter: ';'}); err != nil {
+			t.Fatalf("Unmarshal() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(got, records) {
+			t.Errorf("Unmarshal() = %v, want %v", got, records)
+		}
+	})
+
+	t.Run("Comment lines skipped", func(t *testing.T) {
+		var got [][]string
+		err := csv.Unmarshal([]byte("# exported 2024-01-01\na;b\n# note\nc;d\n"), &got, csv.Options{Delimiter: ';', Comment: '#'})
+		if err != nil {
+			t.Fatalf("Unmarshal() unexpected error = %v", err)
+		}
+		if want := [][]string{{"a", "b"}, {"c", "d"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Unmarshal() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Zero options default to comma", func(t *testing.T) {
+		data, err := csv.Marshal(records, csv.Options{})
+		if err != nil || !strings.HasPrefix(string(data), "name,city\n") {
+			t.Errorf("Marshal() = %q, %v, want comma-separated", data, err)
+		}
+	})
+
+	t.Run("Maps with delimiter", func(t *testing.T) {
+		path := filepath.Join(tempDir, "maps.csv")
+		os.WriteFile(path, []byte("name;age\nAlice;30\n"), 0600)
+		opts := csv.DefaultOptions()
+		opts.Delimiter = ';'
+		got, err := csv.ReadFileToMaps(path, opts)
+		if err != nil {
+			t.Fatalf("ReadFileToMaps() unexpected error = %v", err)
+		}
+		if want := []map[string]string{{"name": "Alice", "age": "30"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadFileToMaps() = %v, want %v", got, want)
+		}
+	})
+
+	invalid := []struct {
+		name    string
+		opts    csv.Options
+		wantErr string
+	}{
+		{"Quote delimiter", csv.Options{Delimiter: '"'}, "invalid delimiter"},
+		{"Newline delimiter", csv.Options{Delimiter: '\n'}, "invalid delimiter"},
+		{"Carriage return delimiter", csv.Options{Delimiter: '\r'}, "invalid delimiter"},
+		{"Replacement character delimiter", csv.Options{Delimiter: utf8.RuneError}, "invalid delimiter"},
+		{"Comment equals delimiter", csv.Options{Delimiter: ';', Comment: ';'}, "invalid comment character"},
+		{"Newline comment", csv.Options{Comment: '\n'}, "invalid comment character"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tempDir, "invalid.csv")
+			checks := map[string]error{
+				"WriteFileWithOptions": csv.WriteFileWithOptions(records, path, tt.opts),
+				"ReadFile":             csv.ReadFile(filepath.Join(tempDir, "data.csv"), &[][]string{}, tt.opts),
+				"Unmarshal":            csv.Unmarshal([]byte("a,b"), &[][]string{}, tt.opts),
+			}
+			_, checks["Marshal"] = csv.Marshal(records, tt.opts)
+			for fn, err := range checks {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("%s() error = %v, wantErr containing %q", fn, err, tt.wantErr)
+				}
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("WriteFileWithOptions() created a file despite invalid options")
+			}
+		})
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteTo(t *testing.T) {
+	records := [][]string{{"id", "name"}, {"1", "Alice"}, {"2", "Bob, Jr."}}
+
+	t.Run("Buffer", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := csv.WriteTo(&buf, records); err != nil {
+			t.Fatalf("WriteTo() unexpected error = %v", err)
+		}
+		if want := "id,name\n1,Alice\n2,\"Bob, Jr.\"\n"; buf.String() != want {
+			t.Errorf("WriteTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("Gzip writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if err := csv.WriteTo(gz, records, csv.Options{Delimiter: ';'}); err != nil {
+			t.Fatalf("WriteTo() unexpected error = %v", err)
+		}
+		gz.Close()
+		zr, err := gzip.NewReader(&buf)
+		if err != nil {
+			t.Fatalf("gzip.NewReader() unexpected error = %v", err)
+		}
+		data, _ := io.ReadAll(zr)
+		var got [][]string
+		if err := csv.Unmarshal(data, &got, csv.Options{Delimiter: ';'}); err != nil || !reflect.DeepEqual(got, records) {
+			t.Errorf("WriteTo() round trip = %v, %v, want %v", got, err, records)
+		}
+	})
+
+	t.Run("Empty records", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := csv.WriteTo(&buf, nil); err != nil || buf.Len() != 0 {
+			t.Errorf("WriteTo() = %q, %v, want no output", buf.String(), err)
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		w       io.Writer
+		opts    []csv.Options
+		wantErr string
+	}{
+		{"Nil writer", nil, nil, "writer cannot be nil"},
+		{"Writer error", failingWriter{}, nil, "disk full"},
+		{"Invalid delimiter", &bytes.Buffer{}, []csv.Options{{Delimiter: '\n'}}, "invalid delimiter"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.WriteTo(tt.w, records, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WriteTo() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteStructsTo(t *testing.T) {
+	type item struct {
+		SKU   string  `csv:"sku"`
+		Price float64 `csv:"price"`
+		Notes string  `csv:"-"`
+	}
+	items := []item{{SKU: "A-1", Price: 9.99, Notes: "x"}, {SKU: "B-2", Price: 100}}
+
+	t.Run("Matches MarshalStructs", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := csv.WriteStructsTo(&buf, items); err != nil {
+			t.Fatalf("WriteStructsTo() unexpected error = %v", err)
+		}
+		want, _ := csv.MarshalStructs(items)
+		if buf.String() != string(want) {
+			t.Errorf("WriteStructsTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("Tab delimiter", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := csv.WriteStructsTo(&buf, items, csv.Options{Delimiter: '\t'}); err != nil {
+			t.Fatalf("WriteStructsTo() unexpected error = %v", err)
+		}
+		if want := "sku\tprice\nA-1\t9.99\nB-2\t100\n"; buf.String() != want {
+			t.Errorf("WriteStructsTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		w       io.Writer
+		v       any
+		wantErr string
+	}{
+		{"Nil writer", nil, items, "writer cannot be nil"},
+		{"Not a slice", &bytes.Buffer{}, items[0], "data must be a slice of structs"},
+		{"Nil element", &bytes.Buffer{}, []*item{nil}, "element 0 is nil"},
+		{"Writer error", failingWriter{}, items, "disk full"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.WriteStructsTo(tt.w, tt.v)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WriteStructsTo() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppendFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("Two appends concatenate", func(t *testing.T) {
+		path := filepath.Join(tempDir, "logs", "audit.csv")
+		first := [][]string{{"time", "event"}, {"1", "login"}}
+		second := [][]string{{"2", "logout"}, {"3", "login, again"}}
+		if err := csv.AppendFile(first, path); err != nil {
+			t.Fatalf("AppendFile() unexpected error = %v", err)
+		}
+		if err := csv.AppendFile(second, path); err != nil {
+			t.Fatalf("AppendFile() unexpected error = %v", err)
+		}
+		var got [][]string
+		if err := csv.ReadFile(path, &got); err != nil {
+			t.Fatalf("ReadFile() unexpected error = %v", err)
+		}
+		if want := append(first, second...); !reflect.DeepEqual(got, want) {
+			t.Errorf("AppendFile() result = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Existing file without trailing newline", func(t *testing.T) {
+		path := filepath.Join(tempDir, "partial.csv")
+		os.WriteFile(path, []byte("a,b"), 0600)
+		if err := csv.AppendFile([][]string{{"c", "d"}}, path); err != nil {
+			t.Fatalf("AppendFile() unexpected error = %v", err)
+		}
+		raw, _ := os.ReadFile(path)
+		if string(raw) != "a,b\nc,d\n" {
+			t.Errorf("AppendFile() content = %q, want %q", raw, "a,b\nc,d\n")
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		data    [][]string
+		path    string
+		wantErr string
+	}{
+		{"Empty records", [][]string{}, filepath.Join(tempDir, "empty.csv"), "records cannot be empty"},
+		{"Invalid extension", [][]string{{"a"}}, filepath.Join(tempDir, "data.txt"), "file must have .csv extension"},
+		{"Empty path", [][]string{{"a"}}, "", "path cannot be empty or root"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.AppendFile(tt.data, tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AppendFile() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestMarshal(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -298,6 +659,86 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalStructs(t *testing.T) {
+	type user struct {
+		Name    string  `csv:"name"`
+		Age     int     `csv:"age"`
+		Score   float64 `csv:"score"`
+		Active  bool    `csv:"active"`
+		Visits  uint16
+		Secret  string `csv:"-"`
+		private string
+	}
+	type withSlice struct {
+		Tags []string `csv:"tags"`
+	}
+
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		err      string
+	}{
+		{
+			name:     "slice of structs",
+			input:    []user{{Name: "Alice", Age: 30, Score: 9.5, Active: true, Visits: 3, Secret: "x", private: "y"}, {Name: "Bob, Jr.", Age: -1, Score: 1e21}},
+			expected: "name,age,score,active,Visits\nAlice,30,9.5,true,3\n\"Bob, Jr.\",-1,1e+21,false,0\n",
+		},
+		{
+			name:     "slice of pointers",
+			input:    []*user{{Name: "Carol", Age: 41}},
+			expected: "name,age,score,active,Visits\nCarol,41,0,false,0\n",
+		},
+		{
+			name:     "array of structs",
+			input:    [1]user{{Name: "Dan"}},
+			expected: "name,age,score,active,Visits\nDan,0,0,false,0\n",
+		},
+		{
+			name:     "empty slice writes header only",
+			input:    []user{},
+			expected: "name,age,score,active,Visits\n",
+		},
+		{
+			name:  "nil element",
+			input: []*user{nil},
+			err:   "element 0 is nil",
+		},
+		{
+			name:  "unsupported field kind",
+			input: []withSlice{{Tags: []string{"a"}}},
+			err:   "field Tags: unsupported field kind slice",
+		},
+		{
+			name:  "not a slice",
+			input: user{Name: "Alice"},
+			err:   "data must be a slice of structs",
+		},
+		{
+			name:  "slice of non-structs",
+			input: []int{1, 2},
+			err:   "data must be a slice of structs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := csv.MarshalStructs(tt.input)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("MarshalStructs() error = %v, wantErr containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("MarshalStructs() unexpected error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("MarshalStructs() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	tests := []struct {
 		name string
@@ -357,3 +798,555 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestReadFileToMaps(t *testing.T) {
+	tempDir := t.TempDir()
+	singlePath := filepath.Join(tempDir, "single.csv")
+	multiPath := filepath.Join(tempDir, "multi.csv")
+	headerOnlyPath := filepath.Join(tempDir, "header.csv")
+	semicolonPath := filepath.Join(tempDir, "semicolon.csv")
+	invalidExtPath := filepath.Join(tempDir, "test.txt")
+
+	// Setup test files
+	os.WriteFile(singlePath, []byte("name,age\nAlice,30\nBob,25\n"), 0600)
+	os.WriteFile(semicolonPath, []byte("name;age\nAlice;30\n"), 0600)
+	os.WriteFile(multiPath, []byte("Person,Q1,Q1\nname,revenue,cost\nAlice,100,40\n"), 0600)
+	os.WriteFile(headerOnlyPath, []byte("name,age\n"), 0600)
+	os.WriteFile(invalidExtPath, []byte("dummy"), 0600)
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    []csv.Options
+		want    []map[string]string
+		wantErr string
+	}{
+		{
+			name: "Default single header row",
+			path: singlePath,
+			want: []map[string]string{
+				{"name": "Alice", "age": "30"},
+				{"name": "Bob", "age": "25"},
+			},
+		},
+		{
+			name: "Two header rows combined",
+			path: multiPath,
+			opts: []csv.Options{{HeaderRows: 2}},
+			want: []map[string]string{
+				{"Person.name": "Alice", "Q1.revenue": "100", "Q1.cost": "40"},
+			},
+		},
+		{
+			name: "No header",
+			path: singlePath,
+			opts: []csv.Options{{HeaderRows: 0}},
+			want: []map[string]string{
+				{"0": "name", "1": "age"},
+				{"0": "Alice", "1": "30"},
+				{"0": "Bob", "1": "25"},
+			},
+		},
+		{
+			name: "Header only",
+			path: headerOnlyPath,
+			want: []map[string]string{},
+		},
+		{
+			name:    "Fewer rows than header rows",
+			path:    headerOnlyPath,
+			opts:    []csv.Options{{HeaderRows: 2}},
+			wantErr: "fewer than 2 header rows",
+		},
+		{
+			name: "Delimiter from DefaultOptions keeps header",
+			path: semicolonPath,
+			opts: []csv.Options{func() csv.Options { o := csv.DefaultOptions(); o.Delimiter = ';'; return o }()},
+			want: []map[string]string{
+				{"name": "Alice", "age": "30"},
+			},
+		},
+		{
+			name: "Delimiter literal has no header",
+			path: semicolonPath,
+			opts: []csv.Options{{Delimiter: ';'}},
+			want: []map[string]string{
+				{"0": "name", "1": "age"},
+				{"0": "Alice", "1": "30"},
+			},
+		},
+		{
+			name:    "Negative header rows",
+			path:    singlePath,
+			opts:    []csv.Options{{HeaderRows: -1}},
+			wantErr: "header rows must be non-negative",
+		},
+		{
+			name:    "Invalid extension",
+			path:    invalidExtPath,
+			wantErr: "file must have .csv extension",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := csv.ReadFileToMaps(tt.path, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadFileToMaps() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadFileToMaps() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadFileToMaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"valid.csv":     "name,age\nAlice,30\nBob,25\n",
+		"header.csv":    "name,age\n",
+		"duplicate.csv": "name,age,name\nAlice,30,Al\n",
+		"short.csv":     "name,age\nAlice,30\nBob\n",
+		"long.csv":      "name,age\nAlice,30,extra\n",
+		"empty.csv":     "",
+		"test.txt":      "name\nAlice\n",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0600)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    []map[string]string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			file: "valid.csv",
+			want: []map[string]string{
+				{"name": "Alice", "age": "30"},
+				{"name": "Bob", "age": "25"},
+			},
+		},
+		{
+			name: "Header only",
+			file: "header.csv",
+			want: []map[string]string{},
+		},
+		{
+			name:    "Duplicate header",
+			file:    "duplicate.csv",
+			wantErr: `duplicate header "name" in columns 1 and 3`,
+		},
+		{
+			name:    "Row with too few columns",
+			file:    "short.csv",
+			wantErr: "row 3 has 1 columns, expected 2",
+		},
+		{
+			name:    "Row with too many columns",
+			file:    "long.csv",
+			wantErr: "row 2 has 3 columns, expected 2",
+		},
+		{
+			name:    "Empty file",
+			file:    "empty.csv",
+			wantErr: "file is empty",
+		},
+		{
+			name:    "Invalid extension",
+			file:    "test.txt",
+			wantErr: "file must have .csv extension",
+		},
+		{
+			name:    "Nonexistent file",
+			file:    "missing.csv",
+			wantErr: "file does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := csv.ReadRecords(filepath.Join(tempDir, tt.file))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadRecords() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadRecords() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadRecords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "\ufeffname,age\nAlice,30\n"
+	path := filepath.Join(tempDir, "excel.csv")
+	os.WriteFile(path, []byte(content), 0600)
+	want := [][]string{{"name", "age"}, {"Alice", "30"}}
+
+	t.Run("ReadFile", func(t *testing.T) {
+		var got [][]string
+		if err := csv.ReadFile(path, &got); err != nil {
+			t.Fatalf("ReadFile() unexpected error = %v", err)
+		}
+		if got[0][0] != "name" {
+			t.Errorf("ReadFile() first header = %q, want %q", got[0][0], "name")
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadFile() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var got [][]string
+		if err := csv.Unmarshal([]byte(content), &got); err != nil {
+			t.Fatalf("Unmarshal() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unmarshal() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ReadFileToMaps", func(t *testing.T) {
+		got, err := csv.ReadFileToMaps(path)
+		if err != nil {
+			t.Fatalf("ReadFileToMaps() unexpected error = %v", err)
+		}
+		if got[0]["name"] != "Alice" {
+			t.Errorf("ReadFileToMaps() = %v, want name column Alice", got)
+		}
+	})
+
+	t.Run("BOM only", func(t *testing.T) {
+		var got [][]string
+		if err := csv.Unmarshal([]byte("\ufeff"), &got); err == nil || !strings.Contains(err.Error(), "no records found") {
+			t.Errorf("Unmarshal() error = %v, wantErr containing %q", err, "no records found")
+		}
+	})
+
+	t.Run("BOM inside data is kept", func(t *testing.T) {
+		var got [][]string
+		if err := csv.Unmarshal([]byte("a,\ufeffb\n"), &got); err != nil {
+			t.Fatalf("Unmarshal() unexpected error = %v", err)
+		}
+		if got[0][1] != "\ufeffb" {
+			t.Errorf("Unmarshal() = %q, want BOM kept in second cell", got)
+		}
+	})
+}
+
+func TestTransformFile(t *testing.T) {
+	tempDir := t.TempDir()
+	srcPath := filepath.Join(tempDir, "src.csv")
+	var b strings.Builder
+	b.WriteString("name,age\n")
+	for i := 0; i < 1000; i++ {
+		b.WriteString("user" + strconv.Itoa(i) + "," + strconv.Itoa(i%100) + "\n")
+	}
+	os.WriteFile(srcPath, []byte(b.String()), 0600)
+
+	upperAdults := func(row []string) ([]string, bool, error) {
+		age, err := strconv.Atoi(row[1])
+		if err != nil {
+			return nil, false, err
+		}
+		if age < 18 {
+			return nil, false, nil
+		}
+		return []string{strings.ToUpper(row[0]), row[1]}, true, nil
+	}
+
+	t.Run("Transform and filter", func(t *testing.T) {
+		dstPath := filepath.Join(tempDir, "out", "adults.csv")
+		if err := csv.TransformFile(srcPath, dstPath, upperAdults); err != nil {
+			t.Fatalf("TransformFile() unexpected error = %v", err)
+		}
+		var got [][]string
+		if err := csv.ReadFile(dstPath, &got); err != nil {
+			t.Fatalf("ReadFile() unexpected error = %v", err)
+		}
+		if len(got) != 1+820 {
+			t.Fatalf("TransformFile() wrote %d records, want %d", len(got), 1+820)
+		}
+		if !reflect.DeepEqual(got[0], []string{"name", "age"}) {
+			t.Errorf("TransformFile() header = %v, want [name age]", got[0])
+		}
+		if !reflect.DeepEqual(got[1], []string{"USER18", "18"}) {
+			t.Errorf("TransformFile() first row = %v, want [USER18 18]", got[1])
+		}
+	})
+
+	t.Run("No header rows", func(t *testing.T) {
+		noHeaderPath := filepath.Join(tempDir, "noheader.csv")
+		os.WriteFile(noHeaderPath, []byte("a,1\nb,2\n"), 0600)
+		dstPath := filepath.Join(tempDir, "noheader_out.csv")
+		double := func(row []string) ([]string, bool, error) {
+			return append(row, row[1]+row[1]), true, nil
+		}
+		if err := csv.TransformFile(noHeaderPath, dstPath, double, csv.Options{HeaderRows: 0}); err != nil {
+			t.Fatalf("TransformFile() unexpected error = %v", err)
+		}
+		var got [][]string
+		csv.ReadFile(dstPath, &got)
+		want := [][]string{{"a", "1", "11"}, {"b", "2", "22"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("TransformFile() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Stops on fn error", func(t *testing.T) {
+		dstPath := filepath.Join(tempDir, "failed.csv")
+		calls := 0
+		failing := func(row []string) ([]string, bool, error) {
+			calls++
+			if calls == 3 {
+				return nil, false, errors.New("bad row")
+			}
+			return row, true, nil
+		}
+		err := csv.TransformFile(srcPath, dstPath, failing)
+		if err == nil || err.Error() != "bad row" {
+			t.Errorf("TransformFile() error = %v, want bad row", err)
+		}
+		if calls != 3 {
+			t.Errorf("TransformFile() called fn %d times, want 3", calls)
+		}
+		if _, err := os.Stat(dstPath); !os.IsNotExist(err) {
+			t.Errorf("TransformFile() left partial output: %v", err)
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		src     string
+		dst     string
+		fn      func([]string) ([]string, bool, error)
+		opts    []csv.Options
+		wantErr string
+	}{
+		{"Nil fn", srcPath, filepath.Join(tempDir, "x.csv"), nil, nil, "transform function cannot be nil"},
+		{"Missing source", filepath.Join(tempDir, "missing.csv"), filepath.Join(tempDir, "x.csv"), upperAdults, nil, "file does not exist"},
+		{"Invalid destination extension", srcPath, filepath.Join(tempDir, "x.txt"), upperAdults, nil, "file must have .csv extension"},
+		{"Same file", srcPath, srcPath, upperAdults, nil, "source and destination must be different files"},
+		{"Negative header rows", srcPath, filepath.Join(tempDir, "x.csv"), upperAdults, []csv.Options{{HeaderRows: -1}}, "header rows must be non-negative"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.TransformFile(tt.src, tt.dst, tt.fn, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("TransformFile() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    [][]string
+		keepHeader bool
+		want       [][]string
+	}{
+		{
+			name:       "Duplicates with header",
+			records:    [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}, {"Bob", "25"}, {"Alice", "31"}},
+			keepHeader: true,
+			want:       [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}, {"Alice", "31"}},
+		},
+		{
+			name:       "Header duplicated in data",
+			records:    [][]string{{"name", "age"}, {"name", "age"}, {"Bob", "25"}},
+			keepHeader: true,
+			want:       [][]string{{"name", "age"}, {"name", "age"}, {"Bob", "25"}},
+		},
+		{
+			name:       "Without header",
+			records:    [][]string{{"a", "b"}, {"a", "b"}, {"c", "d"}},
+			keepHeader: false,
+			want:       [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:       "Ambiguous joins are distinct",
+			records:    [][]string{{"a,b", "c"}, {"a", "b,c"}},
+			keepHeader: false,
+			want:       [][]string{{"a,b", "c"}, {"a", "b,c"}},
+		},
+		{
+			name:       "Empty",
+			records:    [][]string{},
+			keepHeader: true,
+			want:       [][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := csv.Distinct(tt.records, tt.keepHeader); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distinct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	records := [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}, {"Alice", "25"}}
+	original := [][]string{{"name", "age"}, {"Bob", "25"}, {"Alice", "30"}, {"Alice", "25"}}
+
+	tests := []struct {
+		name       string
+		keepHeader bool
+		want       [][]string
+	}{
+		{
+			name:       "With header",
+			keepHeader: true,
+			want:       [][]string{{"name", "age"}, {"Alice", "25"}, {"Alice", "30"}, {"Bob", "25"}},
+		},
+		{
+			name:       "Without header",
+			keepHeader: false,
+			want:       [][]string{{"Alice", "25"}, {"Alice", "30"}, {"Bob", "25"}, {"name", "age"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := csv.Sort(records, tt.keepHeader); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sort() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(records, original) {
+				t.Errorf("Sort() mutated input: %v", records)
+			}
+		})
+	}
+}
+
+func TestUnmarshalStructs(t *testing.T) {
+	type user struct {
+		Name   string  `csv:"name"`
+		Age    int     `csv:"age"`
+		Score  float64 `csv:"score"`
+		Active bool    `csv:"active"`
+		Visits uint8
+		Secret string `csv:"-"`
+	}
+	type withSlice struct {
+		Tags []string `csv:"tags"`
+	}
+
+	t.Run("Round trip with MarshalStructs", func(t *testing.T) {
+		in := []user{{Name: "Alice", Age: 30, Score: 9.5, Active: true, Visits: 3}, {Name: "Bob, Jr.", Age: -1}}
+		data, err := csv.MarshalStructs(in)
+		if err != nil {
+			t.Fatalf("MarshalStructs() unexpected error = %v", err)
+		}
+		var got []user
+		if err := csv.UnmarshalStructs(data, &got); err != nil {
+			t.Fatalf("UnmarshalStructs() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(got, in) {
+			t.Errorf("UnmarshalStructs() = %+v, want %+v", got, in)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		data    string
+		dest    any
+		want    any
+		wantErr string
+	}{
+		{
+			name: "Reordered, missing, and extra columns",
+			data: "extra,age,name,Secret\nx,41,Carol,leak\n",
+			dest: &[]user{},
+			want: &[]user{{Name: "Carol", Age: 41}},
+		},
+		{
+			name: "Slice of pointers",
+			data: "name,active\nDan,true\n",
+			dest: &[]*user{},
+			want: &[]*user{{Name: "Dan", Active: true}},
+		},
+		{
+			name: "Empty cells keep zero values",
+			data: "name,age,score\n,,\n",
+			dest: &[]user{},
+			want: &[]user{{}},
+		},
+		{
+			name: "Header only",
+			data: "name,age\n",
+			dest: &[]user{},
+			want: &[]user{},
+		},
+		{
+			name:    "Unparsable int",
+			data:    "name,age\nAlice,30\nBob,x\n",
+			dest:    &[]user{},
+			wantErr: `row 3 column age: cannot parse "x" as int`,
+		},
+		{
+			name:    "Out of range uint8",
+			data:    "Visits\n300\n",
+			dest:    &[]user{},
+			wantErr: `row 2 column Visits: cannot parse "300" as uint8`,
+		},
+		{
+			name:    "Unsupported field kind",
+			data:    "tags\na\n",
+			dest:    &[]withSlice{},
+			wantErr: "row 2 column tags: unsupported field kind slice",
+		},
+		{
+			name:    "Non-pointer destination",
+			data:    "name\nAlice\n",
+			dest:    []user{},
+			wantErr: "destination must be a pointer to a slice of structs",
+		},
+		{
+			name:    "Slice of non-structs",
+			data:    "name\nAlice\n",
+			dest:    &[]string{},
+			wantErr: "destination must be a pointer to a slice of structs",
+		},
+		{
+			name:    "Empty data",
+			data:    "",
+			dest:    &[]user{},
+			wantErr: "CSV data cannot be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csv.UnmarshalStructs([]byte(tt.data), tt.dest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UnmarshalStructs() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("UnmarshalStructs() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Errorf("UnmarshalStructs() dest = %+v, want %+v", tt.dest, tt.want)
+			}
+		})
+	}
+}
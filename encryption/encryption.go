@@ -1,26 +1,68 @@
-// Package encryption provides utilities for AES-GCM encryption and decryption.
+// Package encryption provides utilities for AES-GCM and ChaCha20-Poly1305 encryption and decryption.
 //
-// This package offers a simple interface for encrypting and decrypting text using the AES-GCM algorithm.
-// It supports 128-bit, 192-bit, and 256-bit keys and uses base64 encoding for ciphertext representation.
+// This package offers a simple interface for encrypting and decrypting text, raw bytes, and files using the
+// AES-GCM algorithm, or ChaCha20-Poly1305 on platforms without AES hardware acceleration, as well as HMAC-SHA256
+// signing and verification.
+// It supports 128-bit, 192-bit, and 256-bit AES keys and uses base64 encoding for ciphertext representation.
 // All functions are designed to be secure and easy to use, with proper error handling for invalid inputs
 // and cryptographic operations.
 package encryption
 
 import (
+	"bufio"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// ErrTokenExpired is returned by OpenToken when a token is authentic but its expiry time has passed.
+var ErrTokenExpired = errors.New("token expired")
+
+// ErrAuthenticationFailed is returned by DecryptFile when a chunk of an encrypted file fails authentication.
+var ErrAuthenticationFailed = errors.New("message authentication failed")
+
+const (
+	fileMagic        = "DVEF"   // Magic bytes at the start of files written by EncryptFile
+	fileVersion      = 1        // Version of the encrypted file format
+	fileHeaderSize   = 16       // Magic, version, chunk size, and the 7-byte nonce prefix
+	fileChunkSize    = 64 << 10 // Plaintext bytes per chunk written by EncryptFile
+	maxFileChunkSize = 16 << 20 // Largest chunk size accepted by DecryptFile
 )
 
-// Encryption is a type used to manage AES-GCM encryption and decryption operations.
+// Algorithm identifies the AEAD cipher used by an Encryption instance.
+type Algorithm int
+
+const (
+	// AESGCM selects AES-GCM, the default. It is fastest on CPUs with AES hardware acceleration.
+	AESGCM Algorithm = iota
+	// ChaCha20Poly1305 selects ChaCha20-Poly1305, which is faster and constant-time on CPUs without AES
+	// hardware acceleration. It requires a 32-byte key.
+	ChaCha20Poly1305
+)
+
+// Encryption is a type used to manage AES-GCM or ChaCha20-Poly1305 encryption and decryption operations.
 //
 // It holds the encryption key and provides methods for encrypting and decrypting data.
-// The key must be 16, 24, or 32 bytes long to support AES-128, AES-192, or AES-256, respectively.
+// With the default AESGCM algorithm, the key must be 16, 24, or 32 bytes long to support AES-128, AES-192,
+// or AES-256, respectively. With ChaCha20Poly1305, the key must be 32 bytes long. Both algorithms use a
+// 12-byte nonce and a 16-byte tag, so all methods produce the same output format (nonce||ciphertext) and
+// callers can switch algorithms transparently for newly encrypted data.
 type Encryption struct {
-	Key []byte
+	Key       []byte
+	Algorithm Algorithm
 }
 
 // NewEncryption creates a new Encryption instance with key validation.
@@ -49,6 +91,35 @@ func NewEncryption(key []byte) (*Encryption, error) {
 	return &Encryption{Key: key}, nil
 }
 
+// NewChaCha20 creates a new Encryption instance that uses ChaCha20-Poly1305 instead of AES-GCM.
+//
+// The returned instance supports the same methods as one created by NewEncryption, with the same output
+// format, but data encrypted with one algorithm can only be decrypted with the same algorithm and key.
+//
+// Example:
+//
+//	key := make([]byte, 32)
+//	if _, err := rand.Read(key); err != nil {
+//	    log.Fatal(err)
+//	}
+//	enc, err := NewChaCha20(key)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - key: The encryption key as a byte slice (must be 32 bytes).
+//
+// Returns:
+//   - *Encryption: A pointer to the initialized Encryption instance.
+//   - error: An error if the key length is invalid.
+func NewChaCha20(key []byte) (*Encryption, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, errors.New("invalid key size: must be 32 bytes")
+	}
+	return &Encryption{Key: key, Algorithm: ChaCha20Poly1305}, nil
+}
+
 // Encrypt encrypts the given plaintext using AES-GCM and returns the ciphertext as a base64-encoded string.
 //
 // The plaintext is encrypted using the AES-GCM algorithm, which provides both confidentiality and authenticity.
@@ -71,20 +142,10 @@ func NewEncryption(key []byte) (*Encryption, error) {
 //   - string: The base64-URL-encoded ciphertext (includes nonce).
 //   - error: An error if the encryption process fails (e.g., invalid key or nonce generation failure).
 func (e *Encryption) Encrypt(text string) (string, error) {
-	plainText := []byte(text)
-	block, err := aes.NewCipher(e.Key)
-	if err != nil {
-		return "", err
-	}
-	gcm, err := cipher.NewGCM(block)
+	cipherText, err := e.EncryptBytes([]byte(text))
 	if err != nil {
 		return "", err
 	}
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-	cipherText := gcm.Seal(nonce, nonce, plainText, nil)
 	return base64.URLEncoding.EncodeToString(cipherText), nil
 }
 
@@ -116,21 +177,674 @@ func (e *Encryption) Decrypt(cipherText string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	block, err := aes.NewCipher(e.Key)
+	plainText, err := e.DecryptBytes(data)
 	if err != nil {
 		return "", err
 	}
-	gcm, err := cipher.NewGCM(block)
+	return string(plainText), nil
+}
+
+// EncryptBytes encrypts the given plaintext bytes using AES-GCM and returns the raw ciphertext.
+//
+// It works like Encrypt, but operates on byte slices and skips the base64 encoding, which avoids the size and
+// processing overhead of double-encoding binary data such as images or serialized structs. The returned slice
+// is the random nonce followed by the sealed ciphertext (nonce||ciphertext).
+//
+// Example:
+//
+//	enc, _ := NewEncryption([]byte("16-byte-key12345"))
+//	ciphertext, err := enc.EncryptBytes(imageData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - plaintext: The data to encrypt.
+//
+// Returns:
+//   - []byte: The raw ciphertext (includes nonce).
+//   - error: An error if the encryption process fails (e.g., invalid key or nonce generation failure).
+func (e *Encryption) EncryptBytes(plaintext []byte) ([]byte, error) {
+	return e.seal(plaintext, nil)
+}
+
+// DecryptBytes decrypts raw ciphertext produced by EncryptBytes using AES-GCM and returns the plaintext.
+//
+// The nonce is taken from the start of the ciphertext. If the ciphertext is too short or decryption fails
+// (e.g., due to tampering or incorrect key), an error is returned.
+//
+// Example:
+//
+//	plaintext, err := enc.DecryptBytes(ciphertext)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - ciphertext: The raw ciphertext (nonce||ciphertext) to decrypt.
+//
+// Returns:
+//   - []byte: The decrypted plaintext.
+//   - error: An error if the ciphertext is too short or decryption fails.
+func (e *Encryption) DecryptBytes(ciphertext []byte) ([]byte, error) {
+	return e.open(ciphertext, nil)
+}
+
+// EncryptWithAAD encrypts the given plaintext using AES-GCM, binding it to additional authenticated data (AAD).
+//
+// It works like Encrypt, but the aad value is authenticated along with the ciphertext without being stored in it.
+// DecryptWithAAD must be given exactly the same aad to decrypt the result, which ties the ciphertext to its context
+// (e.g., a user ID or database record key) and prevents it from being replayed elsewhere, such as copied into
+// another user's record. A nil or empty aad is equivalent to using Encrypt.
+//
+// Example:
+//
+//	enc, _ := NewEncryption([]byte("16-byte-key12345"))
+//	ciphertext, err := enc.EncryptWithAAD("4111-1111-1111-1111", []byte("user:42"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - text: The plaintext string to encrypt.
+//   - aad: The additional authenticated data to bind the ciphertext to.
+//
+// Returns:
+//   - string: The base64-URL-encoded ciphertext (includes nonce, but not the aad).
+//   - error: An error if the encryption process fails (e.g., invalid key or nonce generation failure).
+func (e *Encryption) EncryptWithAAD(text string, aad []byte) (string, error) {
+	cipherText, err := e.seal([]byte(text), aad)
 	if err != nil {
 		return "", err
 	}
-	if len(data) < gcm.NonceSize() {
-		return "", errors.New("ciphertext too short")
+	return base64.URLEncoding.EncodeToString(cipherText), nil
+}
+
+// DecryptWithAAD decrypts a base64-encoded ciphertext produced by EncryptWithAAD and returns the plaintext.
+//
+// The aad must match the value given to EncryptWithAAD byte for byte; otherwise, authentication fails and an
+// error is returned, just as for a tampered ciphertext or an incorrect key.
+//
+// Example:
+//
+//	plaintext, err := enc.DecryptWithAAD(ciphertext, []byte("user:42"))
+//	if err != nil {
+//	    log.Fatal(err) // Wrong key, tampered ciphertext, or ciphertext from another context
+//	}
+//
+// Parameters:
+//   - cipherText: The base64-URL-encoded ciphertext to decrypt.
+//   - aad: The additional authenticated data the ciphertext was bound to.
+//
+// Returns:
+//   - string: The decrypted plaintext string.
+//   - error: An error if the ciphertext is invalid, too short, or decryption fails (including an aad mismatch).
+func (e *Encryption) DecryptWithAAD(cipherText string, aad []byte) (string, error) {
+	data, err := base64.URLEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
 	}
-	nonce, ct := data[:gcm.NonceSize()], data[gcm.NonceSize():]
-	plainText, err := gcm.Open(nil, nonce, ct, nil)
+	plainText, err := e.open(data, aad)
 	if err != nil {
 		return "", err
 	}
 	return string(plainText), nil
 }
+
+// EncryptFile encrypts the file at srcPath with AES-GCM and writes the result to dstPath.
+//
+// The file is streamed in chunks of 64 KiB, so files of any size can be encrypted with constant memory use.
+// The output is written to a temporary file in the destination directory and renamed into place only once
+// encryption has succeeded, so dstPath never holds partial output. The destination is created with 0600
+// permissions. Use DecryptFile with the same key to reverse the operation.
+//
+// The encrypted file format is a 16-byte header followed by a sequence of sealed chunks:
+//
+//	header: magic "DVEF" (4 bytes) | version 1 (1 byte) | chunk size (4 bytes, big-endian) | nonce prefix (7 bytes)
+//	chunk:  AEAD ciphertext of up to chunk-size plaintext bytes, plus a 16-byte authentication tag
+//
+// Every chunk except the last holds exactly chunk-size plaintext bytes; the last holds the remaining 1 to chunk-size
+// bytes, or none for an empty file. Each chunk is sealed with a 12-byte nonce made of the nonce prefix | chunk index (4
+// bytes, big-endian) | last-chunk flag (1 byte, 1 for the final chunk, 0 otherwise), and with the header as additional
+// authenticated data. This binds every chunk to its position and to the file, so reordered, duplicated, truncated, or
+// appended chunks, as well as a modified header, are detected by DecryptFile.
+//
+// Example:
+//
+//	enc, _ := NewEncryption(key)
+//	if err := enc.EncryptFile("backup.tar", "backup.tar.enc"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - srcPath: The path of the plaintext file to encrypt.
+//   - dstPath: The path where the encrypted file will be written.
+//
+// Returns:
+//   - error: An error if the paths are invalid or the same file, the key is invalid, or reading or writing fails.
+func (e *Encryption) EncryptFile(srcPath, dstPath string) error {
+	return e.processFile(srcPath, dstPath, e.encryptStream)
+}
+
+// DecryptFile decrypts a file produced by EncryptFile at srcPath and writes the plaintext to dstPath.
+//
+// The file is streamed chunk by chunk, and every chunk is authenticated before it is written. If any chunk fails
+// authentication (e.g., due to tampering, truncation, or an incorrect key), decryption stops with an error wrapping
+// ErrAuthenticationFailed that names the failing chunk. Because the plaintext is written to a temporary file that is
+// only renamed to dstPath on success, unauthenticated data is never left at dstPath. The destination is created
+// with 0600 permissions. See EncryptFile for the file format.
+//
+// Example:
+//
+//	err := enc.DecryptFile("backup.tar.enc", "backup.tar")
+//	if errors.Is(err, ErrAuthenticationFailed) {
+//	    log.Fatal("backup is corrupted or was encrypted with another key")
+//	}
+//
+// Parameters:
+//   - srcPath: The path of the encrypted file.
+//   - dstPath: The path where the decrypted file will be written.
+//
+// Returns:
+//   - error: An error if the paths are invalid or the same file, the file is not in the expected format,
+//     authentication fails, or reading or writing fails.
+func (e *Encryption) DecryptFile(srcPath, dstPath string) error {
+	return e.processFile(srcPath, dstPath, e.decryptStream)
+}
+
+// processFile streams srcPath through process into a temporary file that replaces dstPath on success.
+func (e *Encryption) processFile(srcPath, dstPath string, process func(r *bufio.Reader, w io.Writer) error) error {
+	if srcPath == "" || dstPath == "" {
+		return errors.New("source and destination paths cannot be empty")
+	}
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	srcInfo, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if srcInfo.IsDir() {
+		return fmt.Errorf("path %s is a directory, not a file", srcPath)
+	}
+	if dstInfo, err := os.Stat(dstPath); err == nil && os.SameFile(srcInfo, dstInfo) {
+		return errors.New("source and destination must be different files")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dstPath), "."+filepath.Base(dstPath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once the rename has succeeded
+
+	w := bufio.NewWriterSize(tmp, fileChunkSize)
+	if err := process(bufio.NewReaderSize(src, fileChunkSize), w); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dstPath)
+}
+
+// encryptStream writes the file header followed by the sealed chunks of r to w.
+func (e *Encryption) encryptStream(r *bufio.Reader, w io.Writer) error {
+	aead, err := e.newAEAD()
+	if err != nil {
+		return err
+	}
+	header := make([]byte, fileHeaderSize)
+	copy(header, fileMagic)
+	header[4] = fileVersion
+	binary.BigEndian.PutUint32(header[5:9], fileChunkSize)
+	if _, err := io.ReadFull(rand.Reader, header[9:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	plain := make([]byte, fileChunkSize)
+	sealed := make([]byte, 0, fileChunkSize+aead.Overhead())
+	for index := uint32(0); ; index++ {
+		n, last, err := readChunk(r, plain)
+		if err != nil {
+			return err
+		}
+		if !last && index == math.MaxUint32 {
+			return errors.New("file too large to encrypt")
+		}
+		sealed = aead.Seal(sealed[:0], chunkNonce(header[9:], index, last), plain[:n], header)
+		if _, err := w.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// decryptStream reads the file header and the sealed chunks from r and writes the authenticated plaintext to w.
+func (e *Encryption) decryptStream(r *bufio.Reader, w io.Writer) error {
+	aead, err := e.newAEAD()
+	if err != nil {
+		return err
+	}
+	header := make([]byte, fileHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errors.New("file is too short to be encrypted")
+		}
+		return err
+	}
+	if string(header[:4]) != fileMagic {
+		return errors.New("file is not in the encrypted file format")
+	}
+	if header[4] != fileVersion {
+		return fmt.Errorf("unsupported encrypted file version %d", header[4])
+	}
+	chunkSize := binary.BigEndian.Uint32(header[5:9])
+	if chunkSize == 0 || chunkSize > maxFileChunkSize {
+		return fmt.Errorf("unsupported chunk size %d", chunkSize)
+	}
+
+	sealed := make([]byte, int(chunkSize)+aead.Overhead())
+	var plain []byte
+	for index := uint32(0); ; index++ {
+		n, last, err := readChunk(r, sealed)
+		if err != nil {
+			return err
+		}
+		plain, err = aead.Open(plain[:0], chunkNonce(header[9:], index, last), sealed[:n], header)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", index, ErrAuthenticationFailed)
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if index == math.MaxUint32 {
+			return fmt.Errorf("chunk %d: %w", index+1, ErrAuthenticationFailed)
+		}
+	}
+}
+
+// readChunk fills buf from r and reports whether the chunk read is the last one in the stream.
+func readChunk(r *bufio.Reader, buf []byte) (n int, last bool, err error) {
+	n, err = io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return n, true, nil
+	}
+	if err != nil {
+		return n, false, err
+	}
+	if _, err := r.Peek(1); err == io.EOF {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	return n, false, nil
+}
+
+// chunkNonce builds the nonce for a file chunk from the nonce prefix, the chunk index, and the last-chunk flag.
+func chunkNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, len(prefix)+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], index)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// SealToken encrypts a payload into a URL-safe token that expires after the given time-to-live.
+//
+// The expiry time is stored in clear at the start of the token and bound to the ciphertext as AES-GCM
+// additional authenticated data, so it cannot be altered without OpenToken detecting the tampering.
+// This is useful for magic links, password resets, and other short-lived, self-contained tokens.
+//
+// Example:
+//
+//	enc, _ := NewEncryption([]byte("16-byte-key12345"))
+//	token, err := enc.SealToken([]byte("user:42"), 15*time.Minute)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(token) // Prints a base64-URL-encoded token
+//
+// Parameters:
+//   - payload: The data to embed in the token.
+//   - ttl: How long the token remains valid (must be positive).
+//
+// Returns:
+//   - string: The base64-URL-encoded token (includes the expiry and nonce).
+//   - error: An error if ttl is not positive or the encryption process fails.
+func (e *Encryption) SealToken(payload []byte, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.New("ttl must be positive")
+	}
+	gcm, err := e.newAEAD()
+	if err != nil {
+		return "", err
+	}
+	expiry := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(ttl).UnixNano()))
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	token := append(expiry, nonce...)
+	token = gcm.Seal(token, nonce, payload, expiry)
+	return base64.URLEncoding.EncodeToString(token), nil
+}
+
+// OpenToken verifies and decrypts a token produced by SealToken and returns its payload.
+//
+// The token's authenticity, including its expiry time, is verified before the expiry is checked, so a tampered
+// token is always rejected as invalid. If the token is authentic but has expired, ErrTokenExpired is returned.
+//
+// Example:
+//
+//	payload, err := enc.OpenToken(token)
+//	if errors.Is(err, ErrTokenExpired) {
+//	    log.Fatal("link expired")
+//	}
+//	fmt.Println(string(payload)) // Prints "user:42"
+//
+// Parameters:
+//   - token: The base64-URL-encoded token to open.
+//
+// Returns:
+//   - []byte: The decrypted payload.
+//   - error: ErrTokenExpired if the token has expired, or an error if the token is invalid, too short, or decryption fails.
+func (e *Encryption) OpenToken(token string) ([]byte, error) {
+	data, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := e.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 8+gcm.NonceSize() {
+		return nil, errors.New("token too short")
+	}
+	expiry, nonce, ct := data[:8], data[8:8+gcm.NonceSize()], data[8+gcm.NonceSize():]
+	payload, err := gcm.Open(nil, nonce, ct, expiry)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().UnixNano() > int64(binary.BigEndian.Uint64(expiry)) {
+		return nil, ErrTokenExpired
+	}
+	return payload, nil
+}
+
+// KeyRing encrypts with a primary key and decrypts with any of several keys, enabling zero-downtime key rotation.
+//
+// Ciphertexts produced by a KeyRing are prefixed with a 4-byte key ID, derived from a SHA-256 fingerprint of each
+// key and its algorithm, so Decrypt can go straight to the right key. The ID is stable, so keys can be added and
+// retired in any order without re-encrypting data. A KeyRing is safe for concurrent use, since it is not modified
+// after creation; to rotate keys, create a new KeyRing with the new primary key first.
+type KeyRing struct {
+	keys []*Encryption
+	ids  [][keyIDSize]byte
+}
+
+// keyIDSize is the length of the key ID prefixed to ciphertexts produced by a KeyRing.
+const keyIDSize = 4
+
+// NewKeyRing creates a KeyRing from an ordered list of keys, the first of which is the primary key.
+//
+// The primary key encrypts all new data; the remaining keys are only used for decryption, typically because
+// they were the primary key of an earlier rotation.
+//
+// Example:
+//
+//	newKey, _ := NewEncryption(newKeyBytes)
+//	oldKey, _ := NewEncryption(oldKeyBytes)
+//	ring, err := NewKeyRing(newKey, oldKey)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	ciphertext, _ := ring.Encrypt("secret")     // Encrypted with newKey
+//	plaintext, _ := ring.Decrypt(oldCiphertext) // Decrypted with oldKey
+//
+// Parameters:
+//   - keys: The keys in order of preference, starting with the primary key.
+//
+// Returns:
+//   - *KeyRing: A pointer to the initialized KeyRing.
+//   - error: An error if no keys are given, a key is nil or has an invalid size, or a key appears twice.
+func NewKeyRing(keys ...*Encryption) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key ring needs at least one key")
+	}
+	ring := &KeyRing{keys: make([]*Encryption, len(keys)), ids: make([][keyIDSize]byte, len(keys))}
+	for i, key := range keys {
+		if key == nil {
+			return nil, fmt.Errorf("key %d is nil", i)
+		}
+		if _, err := key.newAEAD(); err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		h := sha256.New()
+		h.Write([]byte("devify-utils keyring id"))
+		h.Write([]byte{byte(key.Algorithm)})
+		h.Write(key.Key)
+		copy(ring.ids[i][:], h.Sum(nil))
+		for j := range i {
+			if ring.ids[j] == ring.ids[i] {
+				return nil, fmt.Errorf("keys %d and %d have the same key ID", j, i)
+			}
+		}
+		ring.keys[i] = key
+	}
+	return ring, nil
+}
+
+// Encrypt encrypts the given plaintext with the primary key and returns the key-ID-prefixed ciphertext as a
+// base64-URL-encoded string.
+//
+// Parameters:
+//   - text: The plaintext string to encrypt.
+//
+// Returns:
+//   - string: The base64-URL-encoded ciphertext (includes the key ID and nonce).
+//   - error: An error if the encryption process fails.
+func (k *KeyRing) Encrypt(text string) (string, error) {
+	cipherText, err := k.keys[0].EncryptBytes([]byte(text))
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, 0, keyIDSize+len(cipherText))
+	data = append(append(data, k.ids[0][:]...), cipherText...)
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts a base64-URL-encoded ciphertext produced by the Encrypt method of this or any KeyRing sharing
+// a key with it, or by Encryption.Encrypt with one of its keys.
+//
+// If the ciphertext's key ID matches a key in the ring, that key is used directly. Otherwise, as is the case for
+// ciphertexts produced by Encryption.Encrypt before the KeyRing was adopted, each key is tried in turn until one
+// authenticates successfully.
+//
+// Parameters:
+//   - cipherText: The base64-URL-encoded ciphertext to decrypt.
+//
+// Returns:
+//   - string: The decrypted plaintext string.
+//   - error: An error if the ciphertext is invalid or no key in the ring can decrypt it.
+func (k *KeyRing) Decrypt(cipherText string) (string, error) {
+	data, err := base64.URLEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+	if len(data) >= keyIDSize {
+		for i, id := range k.ids {
+			if [keyIDSize]byte(data[:keyIDSize]) != id {
+				continue
+			}
+			if plainText, err := k.keys[i].DecryptBytes(data[keyIDSize:]); err == nil {
+				return string(plainText), nil
+			}
+			break
+		}
+	}
+	// Fall back to trying every key on unprefixed ciphertexts from Encryption.Encrypt.
+	for _, key := range k.keys {
+		if plainText, err := key.DecryptBytes(data); err == nil {
+			return string(plainText), nil
+		}
+	}
+	return "", errors.New("no key in the key ring could decrypt the ciphertext")
+}
+
+// Sign computes the HMAC-SHA256 signature of message under key.
+//
+// HMAC signatures prove that a message, such as a cookie value or webhook payload, was produced by someone holding
+// the key and has not been modified. Unlike Encrypt, the message itself is not hidden. The key should be at least
+// 32 random bytes and kept secret; see Verify for checking signatures.
+//
+// Example:
+//
+//	signature := Sign(secret, payload)
+//	req.Header.Set("X-Signature", hex.EncodeToString(signature))
+//
+// Parameters:
+//   - key: The secret signing key.
+//   - message: The data to sign.
+//
+// Returns:
+//   - []byte: The 32-byte HMAC-SHA256 signature.
+func Sign(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// Verify reports whether signature is a valid HMAC-SHA256 signature of message under key.
+//
+// The comparison is done in constant time with hmac.Equal, so the time taken does not reveal how much of a
+// forged signature is correct. An empty key never verifies, to guard against a missing secret in configuration.
+//
+// Example:
+//
+//	signature, _ := hex.DecodeString(req.Header.Get("X-Signature"))
+//	if !Verify(secret, body, signature) {
+//	    http.Error(w, "invalid signature", http.StatusUnauthorized)
+//	    return
+//	}
+//
+// Parameters:
+//   - key: The secret signing key.
+//   - message: The signed data.
+//   - signature: The signature to check.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+func Verify(key, message, signature []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+	return hmac.Equal(Sign(key, message), signature)
+}
+
+// SignString computes the HMAC-SHA256 signature of message under key as an unpadded base64-URL string.
+//
+// The output contains only URL-safe characters, so it can be embedded in URLs, cookies, and headers without
+// further escaping. Use VerifyString to check it.
+//
+// Example:
+//
+//	link := "https://example.com/unsubscribe?user=42&sig=" + SignString(secret, []byte("user=42"))
+//
+// Parameters:
+//   - key: The secret signing key.
+//   - message: The data to sign.
+//
+// Returns:
+//   - string: The 43-character base64-URL-encoded signature.
+func SignString(key, message []byte) string {
+	return base64.RawURLEncoding.EncodeToString(Sign(key, message))
+}
+
+// VerifyString reports whether signature is a valid signature of message under key, as produced by SignString.
+//
+// Signatures that are not valid unpadded base64-URL strings are rejected. See Verify for details.
+//
+// Example:
+//
+//	if !VerifyString(secret, []byte("user="+r.URL.Query().Get("user")), r.URL.Query().Get("sig")) {
+//	    http.Error(w, "invalid link", http.StatusForbidden)
+//	    return
+//	}
+//
+// Parameters:
+//   - key: The secret signing key.
+//   - message: The signed data.
+//   - signature: The base64-URL-encoded signature to check.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+func VerifyString(key, message []byte, signature string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return Verify(key, message, decoded)
+}
+
+// newAEAD creates the cipher for the Encryption key and algorithm.
+func (e *Encryption) newAEAD() (cipher.AEAD, error) {
+	if e.Algorithm == ChaCha20Poly1305 {
+		return chacha20poly1305.New(e.Key)
+	}
+	if e.Algorithm != AESGCM {
+		return nil, fmt.Errorf("unsupported algorithm %d", e.Algorithm)
+	}
+	block, err := aes.NewCipher(e.Key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce and aad, returning nonce||ciphertext.
+func (e *Encryption) seal(plaintext, aad []byte) ([]byte, error) {
+	gcm, err := e.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open decrypts nonce||ciphertext produced by seal with the same aad.
+func (e *Encryption) open(ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := e.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ct := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ct, aad)
+}
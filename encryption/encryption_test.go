@@ -3,8 +3,14 @@ package encryption
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestNewEncryption tests the NewEncryption constructor for valid and invalid key sizes.
@@ -203,3 +209,598 @@ func TestDifferentKeyDecrypt(t *testing.T) {
 		t.Error("Decrypt() should fail with different key")
 	}
 }
+
+// TestEncryptDecryptBytes tests EncryptBytes and DecryptBytes round trips, the raw output format, and error cases.
+func TestEncryptDecryptBytes(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal("Failed to generate key:", err)
+	}
+	enc, err := NewEncryption(key)
+	if err != nil {
+		t.Fatal("Failed to create Encryption:", err)
+	}
+
+	binaryData := make([]byte, 4096)
+	if _, err := rand.Read(binaryData); err != nil {
+		t.Fatal("Failed to generate data:", err)
+	}
+	for _, plaintext := range [][]byte{binaryData, []byte("hello"), {}} {
+		cipherText, err := enc.EncryptBytes(plaintext)
+		if err != nil {
+			t.Fatalf("EncryptBytes() unexpected error = %v", err)
+		}
+		if want := 12 + len(plaintext) + 16; len(cipherText) != want {
+			t.Errorf("EncryptBytes() length = %d, want %d (nonce + plaintext + tag)", len(cipherText), want)
+		}
+		got, err := enc.DecryptBytes(cipherText)
+		if err != nil {
+			t.Fatalf("DecryptBytes() unexpected error = %v", err)
+		}
+		if string(got) != string(plaintext) {
+			t.Errorf("DecryptBytes() = %x, want %x", got, plaintext)
+		}
+	}
+
+	// Encrypt is EncryptBytes plus base64, so their outputs are interchangeable.
+	cipherText, _ := enc.EncryptBytes([]byte("interop"))
+	if got, err := enc.Decrypt(base64.URLEncoding.EncodeToString(cipherText)); err != nil || got != "interop" {
+		t.Errorf("Decrypt() of EncryptBytes() output = %q, %v", got, err)
+	}
+	encoded, _ := enc.Encrypt("interop")
+	raw, _ := base64.URLEncoding.DecodeString(encoded)
+	if got, err := enc.DecryptBytes(raw); err != nil || string(got) != "interop" {
+		t.Errorf("DecryptBytes() of Encrypt() output = %q, %v", got, err)
+	}
+
+	tampered := append([]byte(nil), cipherText...)
+	tampered[len(tampered)-1] ^= 0xff
+	errTests := []struct {
+		name       string
+		enc        *Encryption
+		cipherText []byte
+	}{
+		{"Too short", enc, []byte("short")},
+		{"Tampered", enc, tampered},
+		{"Invalid key", &Encryption{Key: []byte("invalid")}, cipherText},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.enc.DecryptBytes(tt.cipherText); err == nil {
+				t.Error("DecryptBytes() should fail")
+			}
+		})
+	}
+	if _, err := (&Encryption{Key: []byte("invalid")}).EncryptBytes([]byte("x")); err == nil {
+		t.Error("EncryptBytes() should fail with invalid key size")
+	}
+}
+
+// TestEncryptDecryptWithAAD tests that ciphertexts are bound to their additional authenticated data.
+func TestEncryptDecryptWithAAD(t *testing.T) {
+	enc, err := NewEncryption([]byte("16-byte-key12345"))
+	if err != nil {
+		t.Fatal("Failed to create Encryption:", err)
+	}
+	cipherText, err := enc.EncryptWithAAD("secret", []byte("user:42"))
+	if err != nil {
+		t.Fatalf("EncryptWithAAD() unexpected error = %v", err)
+	}
+	if got, err := enc.DecryptWithAAD(cipherText, []byte("user:42")); err != nil || got != "secret" {
+		t.Errorf("DecryptWithAAD() = %q, %v, want %q", got, err, "secret")
+	}
+
+	tests := []struct {
+		name string
+		aad  []byte
+	}{
+		{"Different AAD", []byte("user:43")},
+		{"AAD prefix", []byte("user:4")},
+		{"AAD with suffix", []byte("user:42 ")},
+		{"Nil AAD", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := enc.DecryptWithAAD(cipherText, tt.aad); err == nil {
+				t.Errorf("DecryptWithAAD() with aad %q should fail", tt.aad)
+			}
+		})
+	}
+	if _, err := enc.Decrypt(cipherText); err == nil {
+		t.Error("Decrypt() of a ciphertext bound to AAD should fail")
+	}
+
+	// Nil and empty AAD are equivalent to plain Encrypt and Decrypt.
+	plain, _ := enc.Encrypt("hello")
+	if got, err := enc.DecryptWithAAD(plain, []byte{}); err != nil || got != "hello" {
+		t.Errorf("DecryptWithAAD() of Encrypt() output = %q, %v", got, err)
+	}
+	withNil, _ := enc.EncryptWithAAD("hello", nil)
+	if got, err := enc.Decrypt(withNil); err != nil || got != "hello" {
+		t.Errorf("Decrypt() of EncryptWithAAD(nil) output = %q, %v", got, err)
+	}
+	if _, err := enc.DecryptWithAAD("invalid-base64-!", nil); err == nil {
+		t.Error("DecryptWithAAD() should fail for invalid base64")
+	}
+	if _, err := (&Encryption{Key: []byte("invalid")}).EncryptWithAAD("x", []byte("a")); err == nil {
+		t.Error("EncryptWithAAD() should fail with invalid key size")
+	}
+}
+
+// TestEncryptDecryptFile tests EncryptFile and DecryptFile round trips across chunk boundaries and detection of
+// tampering, truncation, reordering, and wrong keys.
+func TestEncryptDecryptFile(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal("Failed to generate key:", err)
+	}
+	enc, err := NewEncryption(key)
+	if err != nil {
+		t.Fatal("Failed to create Encryption:", err)
+	}
+	dir := t.TempDir()
+
+	encryptFile := func(t *testing.T, size int) ([]byte, string) {
+		t.Helper()
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal("Failed to generate data:", err)
+		}
+		src := filepath.Join(dir, "plain.bin")
+		if err := os.WriteFile(src, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		dst := filepath.Join(dir, "cipher.enc")
+		if err := enc.EncryptFile(src, dst); err != nil {
+			t.Fatalf("EncryptFile() unexpected error = %v", err)
+		}
+		return data, dst
+	}
+
+	for _, size := range []int{0, 1, fileChunkSize - 1, fileChunkSize, fileChunkSize + 1, 3*fileChunkSize + 5} {
+		t.Run(fmt.Sprintf("Round trip %d bytes", size), func(t *testing.T) {
+			data, encPath := encryptFile(t, size)
+			chunks := max(1, (size+fileChunkSize-1)/fileChunkSize)
+			info, _ := os.Stat(encPath)
+			if want := int64(fileHeaderSize + size + chunks*16); info.Size() != want {
+				t.Errorf("encrypted size = %d, want %d", info.Size(), want)
+			}
+			out := filepath.Join(dir, "decrypted.bin")
+			if err := enc.DecryptFile(encPath, out); err != nil {
+				t.Fatalf("DecryptFile() unexpected error = %v", err)
+			}
+			got, _ := os.ReadFile(out)
+			if string(got) != string(data) {
+				t.Errorf("DecryptFile() content differs from original (%d vs %d bytes)", len(got), len(data))
+			}
+		})
+	}
+
+	_, encPath := encryptFile(t, 2*fileChunkSize+100)
+	original, _ := os.ReadFile(encPath)
+	sealedChunk := fileChunkSize + 16
+	other, _ := NewEncryption(make([]byte, 32))
+
+	tests := []struct {
+		name    string
+		enc     *Encryption
+		modify  func(b []byte) []byte
+		wantErr string
+		wantIs  error
+	}{
+		{
+			name:    "Tampered middle chunk",
+			modify:  func(b []byte) []byte { b[fileHeaderSize+sealedChunk+10] ^= 1; return b },
+			wantErr: "chunk 1: message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name:    "Truncated at chunk boundary",
+			modify:  func(b []byte) []byte { return b[:fileHeaderSize+2*sealedChunk] },
+			wantErr: "chunk 1: message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name: "Reordered chunks",
+			modify: func(b []byte) []byte {
+				first := append([]byte(nil), b[fileHeaderSize:fileHeaderSize+sealedChunk]...)
+				copy(b[fileHeaderSize:], b[fileHeaderSize+sealedChunk:fileHeaderSize+2*sealedChunk])
+				copy(b[fileHeaderSize+sealedChunk:], first)
+				return b
+			},
+			wantErr: "chunk 0: message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name:    "Appended data",
+			modify:  func(b []byte) []byte { return append(b, make([]byte, 32)...) },
+			wantErr: "message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name:    "Tampered header",
+			modify:  func(b []byte) []byte { b[fileHeaderSize-1] ^= 1; return b },
+			wantErr: "chunk 0: message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name:    "Wrong key",
+			enc:     other,
+			modify:  func(b []byte) []byte { return b },
+			wantErr: "chunk 0: message authentication failed",
+			wantIs:  ErrAuthenticationFailed,
+		},
+		{
+			name:    "Not an encrypted file",
+			modify:  func([]byte) []byte { return []byte("just some plain text content") },
+			wantErr: "file is not in the encrypted file format",
+		},
+		{
+			name:    "Too short",
+			modify:  func([]byte) []byte { return []byte("DVEF") },
+			wantErr: "file is too short to be encrypted",
+		},
+		{
+			name:    "Unsupported version",
+			modify:  func(b []byte) []byte { b[4] = 9; return b },
+			wantErr: "unsupported encrypted file version 9",
+		},
+		{
+			name:    "Unsupported chunk size",
+			modify:  func(b []byte) []byte { binary.BigEndian.PutUint32(b[5:9], 1<<30); return b },
+			wantErr: "unsupported chunk size 1073741824",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(dir, "modified.enc")
+			if err := os.WriteFile(src, tt.modify(append([]byte(nil), original...)), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			e := enc
+			if tt.enc != nil {
+				e = tt.enc
+			}
+			dst := filepath.Join(dir, "never-written.bin")
+			err := e.DecryptFile(src, dst)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecryptFile() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("DecryptFile() error = %v, want errors.Is %v", err, tt.wantIs)
+			}
+			if _, statErr := os.Stat(dst); !os.IsNotExist(statErr) {
+				t.Errorf("DecryptFile() left output at %s after failure", dst)
+			}
+		})
+	}
+
+	t.Run("Path errors", func(t *testing.T) {
+		if err := enc.EncryptFile(encPath, encPath); err == nil || !strings.Contains(err.Error(), "must be different files") {
+			t.Errorf("EncryptFile() same file error = %v", err)
+		}
+		if err := enc.EncryptFile(filepath.Join(dir, "missing"), filepath.Join(dir, "out")); err == nil {
+			t.Error("EncryptFile() should fail for a missing source")
+		}
+		if err := enc.EncryptFile(dir, filepath.Join(dir, "out")); err == nil || !strings.Contains(err.Error(), "is a directory") {
+			t.Errorf("EncryptFile() directory error = %v", err)
+		}
+		if err := enc.DecryptFile("", "out"); err == nil || !strings.Contains(err.Error(), "paths cannot be empty") {
+			t.Errorf("DecryptFile() empty path error = %v", err)
+		}
+		invalid := &Encryption{Key: []byte("invalid")}
+		if err := invalid.EncryptFile(encPath, filepath.Join(dir, "out")); err == nil {
+			t.Error("EncryptFile() should fail with invalid key size")
+		}
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if strings.Contains(entry.Name(), ".tmp-") {
+				t.Errorf("temporary file %s was not cleaned up", entry.Name())
+			}
+		}
+	})
+}
+
+// TestChaCha20 tests that ChaCha20-Poly1305 instances support the same API and output format as AES-GCM.
+func TestChaCha20(t *testing.T) {
+	for _, size := range []int{16, 24, 31, 33} {
+		if _, err := NewChaCha20(make([]byte, size)); err == nil || err.Error() != "invalid key size: must be 32 bytes" {
+			t.Errorf("NewChaCha20() with %d-byte key error = %v", size, err)
+		}
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal("Failed to generate key:", err)
+	}
+	chacha, err := NewChaCha20(key)
+	if err != nil {
+		t.Fatalf("NewChaCha20() unexpected error = %v", err)
+	}
+	aesGCM, _ := NewEncryption(key)
+
+	cipherText, err := chacha.Encrypt("Hello, World!")
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error = %v", err)
+	}
+	raw, err := base64.URLEncoding.DecodeString(cipherText)
+	if err != nil || len(raw) != 12+len("Hello, World!")+16 {
+		t.Errorf("Encrypt() output is not base64-URL nonce||ciphertext: %d bytes, %v", len(raw), err)
+	}
+	if got, err := chacha.Decrypt(cipherText); err != nil || got != "Hello, World!" {
+		t.Errorf("Decrypt() = %q, %v", got, err)
+	}
+	if _, err := aesGCM.Decrypt(cipherText); err == nil {
+		t.Error("AES-GCM Decrypt() of a ChaCha20-Poly1305 ciphertext should fail")
+	}
+
+	withAAD, _ := chacha.EncryptWithAAD("bound", []byte("ctx"))
+	if _, err := chacha.DecryptWithAAD(withAAD, []byte("other")); err == nil {
+		t.Error("DecryptWithAAD() with wrong aad should fail")
+	}
+	token, _ := chacha.SealToken([]byte("user:42"), time.Minute)
+	if payload, err := chacha.OpenToken(token); err != nil || string(payload) != "user:42" {
+		t.Errorf("OpenToken() = %q, %v", payload, err)
+	}
+
+	dir := t.TempDir()
+	src, encPath, out := filepath.Join(dir, "plain"), filepath.Join(dir, "enc"), filepath.Join(dir, "out")
+	data := make([]byte, fileChunkSize+10)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := chacha.EncryptFile(src, encPath); err != nil {
+		t.Fatalf("EncryptFile() unexpected error = %v", err)
+	}
+	if err := chacha.DecryptFile(encPath, out); err != nil {
+		t.Fatalf("DecryptFile() unexpected error = %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != string(data) {
+		t.Error("DecryptFile() content differs from original")
+	}
+
+	// The zero value of Algorithm is AES-GCM, so existing struct literals keep working.
+	literal := &Encryption{Key: key}
+	if got, err := literal.Decrypt(mustEncrypt(t, aesGCM, "compat")); err != nil || got != "compat" {
+		t.Errorf("Decrypt() with zero Algorithm = %q, %v", got, err)
+	}
+	if _, err := (&Encryption{Key: key, Algorithm: Algorithm(99)}).Encrypt("x"); err == nil || !strings.Contains(err.Error(), "unsupported algorithm 99") {
+		t.Errorf("Encrypt() with unknown algorithm error = %v", err)
+	}
+}
+
+// mustEncrypt encrypts text with enc, failing the test on error.
+func mustEncrypt(t *testing.T, enc *Encryption, text string) string {
+	t.Helper()
+	cipherText, err := enc.Encrypt(text)
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error = %v", err)
+	}
+	return cipherText
+}
+
+// TestKeyRing tests encryption with the primary key, decryption across rotations, and legacy ciphertexts.
+func TestKeyRing(t *testing.T) {
+	newKey := func(t *testing.T) *Encryption {
+		t.Helper()
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal("Failed to generate key:", err)
+		}
+		enc, err := NewEncryption(key)
+		if err != nil {
+			t.Fatal("Failed to create Encryption:", err)
+		}
+		return enc
+	}
+	oldKey, currentKey, nextKey := newKey(t), newKey(t), newKey(t)
+
+	oldRing, err := NewKeyRing(oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyRing() unexpected error = %v", err)
+	}
+	oldCipherText, _ := oldRing.Encrypt("from old ring")
+	legacyCipherText, _ := oldKey.Encrypt("from plain Encrypt")
+
+	// Rotate: currentKey becomes primary, oldKey stays for decryption.
+	ring, err := NewKeyRing(currentKey, oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyRing() unexpected error = %v", err)
+	}
+	cipherText, err := ring.Encrypt("from new ring")
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error = %v", err)
+	}
+	raw, _ := base64.URLEncoding.DecodeString(cipherText)
+	if got, err := currentKey.DecryptBytes(raw[keyIDSize:]); err != nil || string(got) != "from new ring" {
+		t.Errorf("KeyRing.Encrypt() did not use the primary key: %q, %v", got, err)
+	}
+
+	for _, tt := range []struct{ cipherText, want string }{
+		{cipherText, "from new ring"},
+		{oldCipherText, "from old ring"},
+		{legacyCipherText, "from plain Encrypt"},
+	} {
+		if got, err := ring.Decrypt(tt.cipherText); err != nil || got != tt.want {
+			t.Errorf("Decrypt() = %q, %v, want %q", got, err, tt.want)
+		}
+	}
+
+	// Key IDs are stable, so a ring with keys in a different order still finds the right key.
+	reordered, _ := NewKeyRing(nextKey, oldKey, currentKey)
+	if got, err := reordered.Decrypt(cipherText); err != nil || got != "from new ring" {
+		t.Errorf("Decrypt() after reordering = %q, %v", got, err)
+	}
+
+	// Once oldKey is retired, its ciphertexts no longer decrypt.
+	retired, _ := NewKeyRing(nextKey, currentKey)
+	if _, err := retired.Decrypt(oldCipherText); err == nil || !strings.Contains(err.Error(), "no key in the key ring could decrypt") {
+		t.Errorf("Decrypt() with retired key error = %v", err)
+	}
+
+	tampered := append([]byte(nil), raw...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := ring.Decrypt(base64.URLEncoding.EncodeToString(tampered)); err == nil {
+		t.Error("Decrypt() of tampered ciphertext should fail")
+	}
+	if _, err := ring.Decrypt("invalid-base64-!"); err == nil {
+		t.Error("Decrypt() of invalid base64 should fail")
+	}
+
+	errTests := []struct {
+		name    string
+		keys    []*Encryption
+		wantErr string
+	}{
+		{"No keys", nil, "key ring needs at least one key"},
+		{"Nil key", []*Encryption{currentKey, nil}, "key 1 is nil"},
+		{"Invalid key", []*Encryption{{Key: []byte("short")}}, "key 0: crypto/aes: invalid key size 5"},
+		{"Duplicate key", []*Encryption{currentKey, oldKey, {Key: currentKey.Key}}, "keys 0 and 2 have the same key ID"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyRing(tt.keys...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewKeyRing() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The same key bytes used with different algorithms get different key IDs.
+	chacha, _ := NewChaCha20(currentKey.Key)
+	if _, err := NewKeyRing(currentKey, chacha); err != nil {
+		t.Errorf("NewKeyRing() with same key for two algorithms error = %v", err)
+	}
+}
+
+// TestSignVerify tests HMAC-SHA256 signing against a known vector and rejection of modified inputs.
+func TestSignVerify(t *testing.T) {
+	// RFC 4231 test case 2.
+	key, message := []byte("Jefe"), []byte("what do ya want for nothing?")
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	signature := Sign(key, message)
+	if got := fmt.Sprintf("%x", signature); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if !Verify(key, message, signature) {
+		t.Error("Verify() = false for a valid signature")
+	}
+
+	sigString := SignString(key, message)
+	if len(sigString) != 43 || strings.ContainsAny(sigString, "+/=") {
+		t.Errorf("SignString() = %q, want 43 URL-safe characters", sigString)
+	}
+	if !VerifyString(key, message, sigString) {
+		t.Error("VerifyString() = false for a valid signature")
+	}
+
+	tampered := append([]byte(nil), signature...)
+	tampered[0] ^= 1
+	tests := []struct {
+		name      string
+		key       []byte
+		message   []byte
+		signature []byte
+	}{
+		{"Modified message", key, []byte("what do ya want for nothing!"), signature},
+		{"Wrong key", []byte("jefe"), message, signature},
+		{"Tampered signature", key, message, tampered},
+		{"Truncated signature", key, message, signature[:16]},
+		{"Empty signature", key, message, nil},
+		{"Empty key", nil, message, Sign(nil, message)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Verify(tt.key, tt.message, tt.signature) {
+				t.Error("Verify() = true, want false")
+			}
+			if VerifyString(tt.key, tt.message, base64.RawURLEncoding.EncodeToString(tt.signature)) {
+				t.Error("VerifyString() = true, want false")
+			}
+		})
+	}
+	for _, sig := range []string{sigString + "=", "not base64!", ""} {
+		if VerifyString(key, message, sig) {
+			t.Errorf("VerifyString(%q) = true, want false", sig)
+		}
+	}
+}
+
+// TestSealOpenToken tests SealToken and OpenToken for valid, expired, and tampered tokens.
+func TestSealOpenToken(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal("Failed to generate key:", err)
+	}
+
+	enc, err := NewEncryption(key)
+	if err != nil {
+		t.Fatal("Failed to create Encryption:", err)
+	}
+
+	// Valid token
+	token, err := enc.SealToken([]byte("user:42"), time.Minute)
+	if err != nil {
+		t.Fatal("Failed to seal token:", err)
+	}
+	payload, err := enc.OpenToken(token)
+	if err != nil {
+		t.Errorf("OpenToken() unexpected error = %v", err)
+	}
+	if string(payload) != "user:42" {
+		t.Errorf("OpenToken() = %q, want %q", payload, "user:42")
+	}
+
+	// Expired token
+	expired, err := enc.SealToken([]byte("user:42"), time.Millisecond)
+	if err != nil {
+		t.Fatal("Failed to seal token:", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := enc.OpenToken(expired); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("OpenToken() error = %v, want ErrTokenExpired", err)
+	}
+
+	// Tampered expiry: extending the expiry must fail authentication
+	data, _ := base64.URLEncoding.DecodeString(expired)
+	binary.BigEndian.PutUint64(data, uint64(time.Now().Add(time.Hour).UnixNano()))
+	if _, err := enc.OpenToken(base64.URLEncoding.EncodeToString(data)); err == nil || errors.Is(err, ErrTokenExpired) {
+		t.Errorf("OpenToken() error = %v, want authentication failure for tampered expiry", err)
+	}
+
+	// Tampered ciphertext
+	data, _ = base64.URLEncoding.DecodeString(token)
+	data[len(data)-1] ^= 0xFF
+	if _, err := enc.OpenToken(base64.URLEncoding.EncodeToString(data)); err == nil {
+		t.Error("OpenToken() should fail with tampered ciphertext")
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "Invalid base64",
+			token: "invalid-base64-!",
+		},
+		{
+			name:  "Token too short",
+			token: base64.URLEncoding.EncodeToString([]byte("short")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := enc.OpenToken(tt.token); err == nil {
+				t.Error("OpenToken() should fail")
+			}
+		})
+	}
+
+	if _, err := enc.SealToken([]byte("user:42"), 0); err == nil {
+		t.Error("SealToken() should fail with non-positive ttl")
+	}
+}
@@ -2,21 +2,31 @@
 //
 // This package offers helper functions for validating file paths and ensuring directories exist,
 // designed to be used alongside other packages in the devify-utils library, such as csv and encryption.
-// It includes a Serializer interface for data serialization and file I/O operations, along with
-// standardized error types for common failure cases.
+// It includes a Serializer interface for data serialization and file I/O operations, an in-memory Serializer
+// for tests, a registry of Serializers by file extension for reading files of any supported format, a loader for
+// merging directories of configuration files, struct-tag validation of configuration files, and standardized error
+// types for common failure cases.
 package fileio
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 // Serializer defines an interface for data serialization and file I/O operations.
 //
 // Implementations of this interface provide methods for marshaling and unmarshaling data,
-// as well as reading and writing files. This is used by other packages in devify-utils
-// to handle data in specific formats, such as CSV or encrypted data.
+// as well as reading and writing files. The json, yaml, xml and csv packages each provide an implementation,
+// registered by file extension so that it can be looked up with Get.
 //
 // Methods:
 //   - Marshal: Converts data to a byte slice.
@@ -146,3 +156,404 @@ func EnsureDir(path string, perm os.FileMode) error {
 	}
 	return nil
 }
+
+// LoadDir reads every configuration file in a directory, merges them, and unmarshals the result into dest.
+//
+// Files with a ".json", ".yaml", or ".yml" extension are loaded in lexical order by name, so later files take
+// precedence (e.g., "10-base.yaml" is overridden by "20-local.yaml"); other files and subdirectories are ignored.
+// Each file must contain a mapping at the top level. Mappings are merged recursively key by key, while any other
+// value (including lists) in a later file replaces the earlier value entirely. All files are decoded with
+// gopkg.in/yaml.v3 (JSON is valid YAML), so dest fields are matched using `yaml` struct tags.
+//
+// Example:
+//
+//	var cfg struct {
+//	    Port int    `yaml:"port"`
+//	    Host string `yaml:"host"`
+//	}
+//	err := LoadDir("conf.d", &cfg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(cfg.Port) // Prints the port from the last file that sets it
+//
+// Parameters:
+//   - dir: The directory containing the configuration files.
+//   - dest: A pointer to the destination where the merged configuration will be stored.
+//
+// Returns:
+//   - error: An error if the directory cannot be read, contains no configuration files, a file is invalid
+//     or not a mapping, the destination is nil, or unmarshaling fails.
+func LoadDir(dir string, dest any) error {
+	if dir == "" {
+		return ErrEmptyPath
+	}
+	if len(dir) > 4096 {
+		return ErrPathTooLong
+	}
+	if dest == nil {
+		return errors.New("destination cannot be nil")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	if len(names) == 0 {
+		return errors.New("no configuration files found in " + dir)
+	}
+	sort.Strings(names)
+	merged := map[string]any{}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		var doc map[string]any
+		if err := yamlv3.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		mergeMaps(merged, doc)
+	}
+	output, err := yamlv3.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return yamlv3.Unmarshal(output, dest)
+}
+
+// ValidateAgainst checks that a configuration file is compatible with the type of template without populating any
+// existing value.
+//
+// The file is decoded into a fresh value of template's type (template may be a struct or a pointer to one and is never
+// modified) with the Unmarshal method of the Serializer registered for its extension (see Register), so the struct tags
+// of that format apply (e.g., `json` tags for ".json" and `yaml` tags for ".yaml"/".yml"). Unlike ReadAuto, ".json",
+// ".yaml" and ".yml" files are supported even if their format packages are not imported: they are then decoded with
+// encoding/json and gopkg.in/yaml.v3 directly. The decoded value is then checked against its `validate` struct tags
+// with go-playground/validator. Validation failures are returned as validator.ValidationErrors, which can be inspected
+// with errors.As to get the individual field errors.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port int    `yaml:"port" validate:"required,min=1,max=65535"`
+//	    Host string `yaml:"host" validate:"required,hostname"`
+//	}
+//	if err := ValidateAgainst("config.yaml", Config{}); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: The file path of the configuration file to validate.
+//   - template: A struct value or pointer to a struct whose type describes the expected configuration.
+//
+// Returns:
+//   - error: An error if the path is invalid, the extension is neither ".json", ".yaml", ".yml" nor registered with
+//     Register, the template is not a struct, the file cannot be decoded into the template's type, or validation
+//     fails.
+func ValidateAgainst(path string, template any) error {
+	if template == nil {
+		return errors.New("template cannot be nil")
+	}
+	typ := reflect.TypeOf(template)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("template must be a struct or pointer to struct, got %s", typ)
+	}
+	ext := filepath.Ext(path)
+	// Prefer the registered Serializer, falling back to the built-in JSON and YAML decoders
+	var unmarshal func([]byte, any) error
+	if s, ok := Get(ext); ok {
+		unmarshal = s.Unmarshal
+	} else {
+		switch ext {
+		case ".json":
+			unmarshal = json.Unmarshal
+		case ".yaml", ".yml":
+			unmarshal = yamlv3.Unmarshal
+		default:
+			return fmt.Errorf("unsupported config extension %q", ext)
+		}
+	}
+	if err := ValidateReadPath(path, ext); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dest := reflect.New(typ).Interface()
+	if err := unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return validator.New(validator.WithRequiredStructEnabled()).Struct(dest)
+}
+
+// mergeMaps recursively merges src into dst, with values from src taking precedence.
+func mergeMaps(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// MemSerializer is an in-memory implementation of the Serializer interface.
+//
+// It stores written data in a map keyed by path instead of on disk, using JSON for marshaling and unmarshaling.
+// This allows code that depends on the Serializer interface to be tested without touching the filesystem.
+// A MemSerializer is safe for concurrent use.
+type MemSerializer struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemSerializer creates a new, empty MemSerializer instance.
+//
+// Example:
+//
+//	var s Serializer = NewMemSerializer()
+//	err := s.WriteFile(map[string]string{"key": "value"}, "config.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Returns:
+//   - *MemSerializer: A pointer to the initialized MemSerializer instance.
+func NewMemSerializer() *MemSerializer {
+	return &MemSerializer{files: make(map[string][]byte)}
+}
+
+// Marshal serializes the given data to JSON format as a byte slice.
+//
+// Parameters:
+//   - data: The data to serialize (can be any type supported by encoding/json).
+//
+// Returns:
+//   - []byte: The JSON-encoded data as a byte slice.
+//   - error: An error if the data is nil or cannot be marshaled.
+func (m *MemSerializer) Marshal(data any) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data cannot be nil")
+	}
+	return json.Marshal(data)
+}
+
+// Unmarshal parses JSON data into the provided destination.
+//
+// Parameters:
+//   - data: The JSON-encoded data as a byte slice.
+//   - dest: A pointer to the destination where the parsed data will be stored.
+//
+// Returns:
+//   - error: An error if the data is empty, the destination is nil, or parsing fails.
+func (m *MemSerializer) Unmarshal(data []byte, dest any) error {
+	if len(data) == 0 {
+		return errors.New("data cannot be empty")
+	}
+	if dest == nil {
+		return errors.New("destination cannot be nil")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// ReadFile reads the data stored at the specified in-memory path and unmarshals it into the provided destination.
+//
+// Parameters:
+//   - path: The in-memory path to read.
+//   - dest: A pointer to the destination where the parsed data will be stored.
+//
+// Returns:
+//   - error: ErrEmptyPath if the path is empty or root, ErrFileNotExist if nothing was written to the path,
+//     or an error if unmarshaling fails.
+func (m *MemSerializer) ReadFile(path string, dest any) error {
+	if path == "" || path == "." {
+		return ErrEmptyPath
+	}
+	m.mu.RLock()
+	data, ok := m.files[path]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrFileNotExist
+	}
+	return m.Unmarshal(data, dest)
+}
+
+// WriteFile serializes the given data to JSON and stores it at the specified in-memory path.
+//
+// Any existing data at the path is replaced. The perm argument is accepted to satisfy the Serializer
+// interface but is ignored, as no file is created on disk.
+//
+// Parameters:
+//   - data: The data to serialize and store.
+//   - path: The in-memory path where the data will be stored.
+//   - perm: Optional file permission mode (ignored).
+//
+// Returns:
+//   - error: ErrEmptyPath if the path is empty or root, or an error if the data cannot be marshaled.
+func (m *MemSerializer) WriteFile(data any, path string, perm ...os.FileMode) error {
+	if path == "" || path == "." {
+		return ErrEmptyPath
+	}
+	output, err := m.Marshal(data)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.files[path] = output
+	m.mu.Unlock()
+	return nil
+}
+
+// registry maps file extensions to the Serializer registered for them.
+var registry = struct {
+	mu          sync.RWMutex
+	serializers map[string]Serializer
+}{serializers: make(map[string]Serializer)}
+
+// Register makes a Serializer available for files with the given extension (e.g., ".json").
+//
+// The json, yaml, xml and csv packages of devify-utils register themselves for their extensions in an init function,
+// so importing them (if only for side effects, e.g. `import _ "github.com/devify-me/devify-utils/yaml"`) is enough
+// to make them available through Get and ReadAuto. Registering an extension again replaces the previous Serializer, which
+// allows a custom implementation to take over a format. Register panics if ext does not start with a dot or s is
+// nil, as both are programming errors.
+//
+// Example:
+//
+//	fileio.Register(".toml", tomlSerializer{})
+//
+// Parameters:
+//   - ext: The file extension including the leading dot (e.g., ".json").
+//   - s: The Serializer to use for files with the extension.
+func Register(ext string, s Serializer) {
+	if len(ext) < 2 || ext[0] != '.' {
+		panic(fmt.Sprintf("fileio: invalid extension %q for Register", ext))
+	}
+	if s == nil {
+		panic("fileio: Register serializer is nil for " + ext)
+	}
+	registry.mu.Lock()
+	registry.serializers[ext] = s
+	registry.mu.Unlock()
+}
+
+// DetectFormat returns the extension of a file path if a Serializer is registered for it.
+//
+// The extension is matched exactly, as the format packages validate extensions case-sensitively. Only formats
+// registered with Register are recognized; see Register for how the built-in formats are made available.
+//
+// Example:
+//
+//	ext, err := DetectFormat("config/app.yml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ext) // Prints ".yml"
+//
+// Parameters:
+//   - path: The file path whose format should be detected.
+//
+// Returns:
+//   - string: The file extension, including the leading dot.
+//   - error: An error if the path is empty or too long, or no Serializer is registered for its extension.
+func DetectFormat(path string) (string, error) {
+	if path == "" || path == "." {
+		return "", ErrEmptyPath
+	}
+	if len(path) > 4096 {
+		return "", ErrPathTooLong
+	}
+	ext := filepath.Ext(path)
+	if _, ok := Get(ext); !ok {
+		return "", fmt.Errorf("unsupported file extension %q", ext)
+	}
+	return ext, nil
+}
+
+// ReadAuto reads a file into the provided destination using the Serializer registered for its extension.
+//
+// This lets a loader accept any supported format (e.g., ".json", ".yaml", ".yml", ".xml" or ".csv") without
+// switching on the extension itself. The format is detected with DetectFormat, and the file is read with the
+// Serializer's ReadFile, which performs the usual path validation of its package. The format packages must be
+// imported for their extensions to be registered.
+//
+// Example:
+//
+//	import _ "github.com/devify-me/devify-utils/yaml"
+//
+//	var cfg Config
+//	if err := fileio.ReadAuto("config.yaml", &cfg); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: The file path to read.
+//   - dest: A pointer to the destination where the parsed data will be stored.
+//
+// Returns:
+//   - error: An error if the extension is not supported, or reading or parsing the file fails.
+func ReadAuto(path string, dest any) error {
+	ext, err := DetectFormat(path)
+	if err != nil {
+		return err
+	}
+	s, _ := Get(ext)
+	return s.ReadFile(path, dest)
+}
+
+// Get returns the Serializer registered for a file extension.
+//
+// Example:
+//
+//	s, ok := fileio.Get(".yaml")
+//	if !ok {
+//	    log.Fatal("YAML support is not registered")
+//	}
+//	err := s.WriteFile(cfg, "config.yaml")
+//
+// Parameters:
+//   - ext: The file extension including the leading dot (e.g., ".yaml").
+//
+// Returns:
+//   - Serializer: The Serializer registered for the extension, or nil if there is none.
+//   - bool: True if a Serializer is registered for the extension.
+func Get(ext string) (Serializer, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	s, ok := registry.serializers[ext]
+	return s, ok
+}
+
+// Extensions returns the file extensions with a registered Serializer in sorted order.
+//
+// Example:
+//
+//	fmt.Println(fileio.Extensions()) // Prints [.csv .json .xml .yaml .yml] with all format packages imported
+//
+// Returns:
+//   - []string: The registered extensions, sorted lexically.
+func Extensions() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	exts := make([]string, 0, len(registry.serializers))
+	for ext := range registry.serializers {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
@@ -1,12 +1,20 @@
 package fileio_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
+	_ "github.com/devify-me/devify-utils/csv"
 	"github.com/devify-me/devify-utils/fileio"
+	_ "github.com/devify-me/devify-utils/json"
+	_ "github.com/devify-me/devify-utils/xml"
+	_ "github.com/devify-me/devify-utils/yaml"
+	"github.com/go-playground/validator/v10"
 )
 
 func TestValidateReadPath(t *testing.T) {
@@ -196,3 +204,475 @@ func TestEnsureDir(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadDir(t *testing.T) {
+	type config struct {
+		Name     string   `yaml:"name"`
+		Port     int      `yaml:"port"`
+		Tags     []string `yaml:"tags"`
+		Database struct {
+			Host string `yaml:"host"`
+			User string `yaml:"user"`
+		} `yaml:"database"`
+	}
+
+	confDir := t.TempDir()
+	os.WriteFile(filepath.Join(confDir, "10-base.yaml"), []byte("name: api\nport: 8080\ntags: [a, b]\ndatabase:\n  host: localhost\n  user: admin\n"), 0600)
+	os.WriteFile(filepath.Join(confDir, "20-override.yml"), []byte("port: 9090\ntags: [c]\ndatabase:\n  host: db.internal\n"), 0600)
+	os.WriteFile(filepath.Join(confDir, "30-extra.json"), []byte(`{"name": "api-prod"}`), 0600)
+	os.WriteFile(filepath.Join(confDir, "README.md"), []byte("not config"), 0600)
+	os.Mkdir(filepath.Join(confDir, "sub.yaml"), 0755)
+
+	var cfg config
+	if err := fileio.LoadDir(confDir, &cfg); err != nil {
+		t.Fatalf("LoadDir() unexpected error = %v", err)
+	}
+	if cfg.Name != "api-prod" || cfg.Port != 9090 {
+		t.Errorf("LoadDir() scalars = %q, %d, want %q, %d", cfg.Name, cfg.Port, "api-prod", 9090)
+	}
+	if len(cfg.Tags) != 1 || cfg.Tags[0] != "c" {
+		t.Errorf("LoadDir() Tags = %v, want [c]", cfg.Tags)
+	}
+	if cfg.Database.Host != "db.internal" || cfg.Database.User != "admin" {
+		t.Errorf("LoadDir() Database = %+v, want merged nested mapping", cfg.Database)
+	}
+
+	emptyDir := t.TempDir()
+	invalidDir := t.TempDir()
+	os.WriteFile(filepath.Join(invalidDir, "bad.yaml"), []byte("- just\n- a list\n"), 0600)
+
+	tests := []struct {
+		name    string
+		dir     string
+		dest    any
+		wantErr string
+	}{
+		{
+			name:    "Empty path",
+			dir:     "",
+			dest:    &cfg,
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Nil destination",
+			dir:     confDir,
+			dest:    nil,
+			wantErr: "destination cannot be nil",
+		},
+		{
+			name:    "No config files",
+			dir:     emptyDir,
+			dest:    &cfg,
+			wantErr: "no configuration files found",
+		},
+		{
+			name:    "Not a mapping",
+			dir:     invalidDir,
+			dest:    &cfg,
+			wantErr: "failed to parse bad.yaml",
+		},
+		{
+			name:    "Directory does not exist",
+			dir:     filepath.Join(emptyDir, "missing"),
+			dest:    &cfg,
+			wantErr: "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fileio.LoadDir(tt.dir, tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadDir() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAgainst(t *testing.T) {
+	type config struct {
+		Host string `json:"host" yaml:"host" validate:"required"`
+		Port int    `json:"port" yaml:"port" validate:"min=1,max=65535"`
+	}
+
+	tempDir := t.TempDir()
+	validYAML := filepath.Join(tempDir, "valid.yaml")
+	validJSON := filepath.Join(tempDir, "valid.json")
+	invalidYAML := filepath.Join(tempDir, "invalid.yml")
+	malformedJSON := filepath.Join(tempDir, "malformed.json")
+	unsupported := filepath.Join(tempDir, "config.toml")
+	os.WriteFile(validYAML, []byte("host: localhost\nport: 8080\n"), 0600)
+	os.WriteFile(validJSON, []byte(`{"host": "localhost", "port": 443}`), 0600)
+	os.WriteFile(invalidYAML, []byte("port: 70000\n"), 0600)
+	os.WriteFile(malformedJSON, []byte(`{"host": "localhost", "port": "abc"}`), 0600)
+	os.WriteFile(unsupported, []byte("host = 'x'"), 0600)
+	// A format registered at runtime is decoded with its Serializer, here MemSerializer's JSON Unmarshal
+	fileio.Register(".cfgtest", fileio.NewMemSerializer())
+	registered := filepath.Join(tempDir, "config.cfgtest")
+	os.WriteFile(registered, []byte(`{"host": "localhost", "port": 0}`), 0600)
+
+	template := config{Host: "untouched"}
+
+	tests := []struct {
+		name       string
+		path       string
+		template   any
+		wantErr    string
+		wantFields []string
+	}{
+		{
+			name:     "Valid YAML",
+			path:     validYAML,
+			template: template,
+		},
+		{
+			name:     "Valid JSON with pointer template",
+			path:     validJSON,
+			template: &template,
+		},
+		{
+			name:       "Violates validate tags",
+			path:       invalidYAML,
+			template:   template,
+			wantErr:    "validation for 'Host' failed",
+			wantFields: []string{"Host", "Port"},
+		},
+		{
+			name:     "Type mismatch",
+			path:     malformedJSON,
+			template: template,
+			wantErr:  "failed to parse malformed.json",
+		},
+		{
+			name:       "Registered format",
+			path:       registered,
+			template:   template,
+			wantErr:    "validation for 'Port' failed",
+			wantFields: []string{"Port"},
+		},
+		{
+			name:     "Unsupported extension",
+			path:     unsupported,
+			template: template,
+			wantErr:  "unsupported config extension",
+		},
+		{
+			name:     "File not exist",
+			path:     filepath.Join(tempDir, "missing.yaml"),
+			template: template,
+			wantErr:  "file does not exist",
+		},
+		{
+			name:     "Nil template",
+			path:     validYAML,
+			template: nil,
+			wantErr:  "template cannot be nil",
+		},
+		{
+			name:     "Non-struct template",
+			path:     validYAML,
+			template: map[string]any{},
+			wantErr:  "template must be a struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fileio.ValidateAgainst(tt.path, tt.template)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateAgainst() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateAgainst() error = %v, wantErr containing %q", err, tt.wantErr)
+				return
+			}
+			if tt.wantFields != nil {
+				var verrs validator.ValidationErrors
+				if !errors.As(err, &verrs) {
+					t.Fatalf("ValidateAgainst() error type = %T, want validator.ValidationErrors", err)
+				}
+				var fields []string
+				for _, fe := range verrs {
+					fields = append(fields, fe.Field())
+				}
+				if !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("ValidateAgainst() fields = %v, want %v", fields, tt.wantFields)
+				}
+			}
+		})
+	}
+	if template.Host != "untouched" || template.Port != 0 {
+		t.Errorf("ValidateAgainst() modified template: %+v", template)
+	}
+}
+
+func TestMemSerializer(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "config.json")
+	var s fileio.Serializer = fileio.NewMemSerializer()
+
+	type config struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	want := config{Name: "api", Port: 8080}
+
+	if err := s.WriteFile(want, path, 0644); err != nil {
+		t.Fatalf("WriteFile() unexpected error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("WriteFile() created a real file at %s", path)
+	}
+	var got config
+	if err := s.ReadFile(path, &got); err != nil {
+		t.Fatalf("ReadFile() unexpected error = %v", err)
+	}
+	if got != want {
+		t.Errorf("ReadFile() = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{
+			name:    "Read missing path",
+			run:     func() error { return s.ReadFile(filepath.Join(tempDir, "missing.json"), &got) },
+			wantErr: "file does not exist",
+		},
+		{
+			name:    "Read empty path",
+			run:     func() error { return s.ReadFile("", &got) },
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Write empty path",
+			run:     func() error { return s.WriteFile(want, "") },
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Write nil data",
+			run:     func() error { return s.WriteFile(nil, path) },
+			wantErr: "data cannot be nil",
+		},
+		{
+			name:    "Read nil destination",
+			run:     func() error { return s.ReadFile(path, nil) },
+			wantErr: "destination cannot be nil",
+		},
+		{
+			name:    "Unmarshal empty data",
+			run:     func() error { return s.Unmarshal(nil, &got) },
+			wantErr: "data cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadAuto(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"config.json": `{"name": "app", "port": 8080}`,
+		"config.yaml": "name: app\nport: 8080\n",
+		"config.yml":  "name: app\nport: 8080\n",
+		"config.xml":  "<config><name>app</name><port>8080</port></config>",
+		"config.toml": "name = \"app\"\n",
+		"empty.yaml":  "",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0600)
+	}
+	type config struct {
+		Name string `json:"name" yaml:"name" xml:"name"`
+		Port int    `json:"port" yaml:"port" xml:"port"`
+	}
+	want := config{Name: "app", Port: 8080}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"JSON", filepath.Join(tempDir, "config.json"), ""},
+		{"YAML", filepath.Join(tempDir, "config.yaml"), ""},
+		{"YML", filepath.Join(tempDir, "config.yml"), ""},
+		{"XML", filepath.Join(tempDir, "config.xml"), ""},
+		{"Unknown extension", filepath.Join(tempDir, "config.toml"), `unsupported file extension ".toml"`},
+		{"No extension", filepath.Join(tempDir, "config"), `unsupported file extension ""`},
+		{"Nonexistent file", filepath.Join(tempDir, "missing.json"), fileio.ErrFileNotExist.Error()},
+		{"Empty file", filepath.Join(tempDir, "empty.yaml"), "file is empty"},
+		{"Empty path", "", fileio.ErrEmptyPath.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got config
+			err := fileio.ReadAuto(tt.path, &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadAuto() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadAuto() unexpected error = %v", err)
+			}
+			if got != want {
+				t.Errorf("ReadAuto() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		path := filepath.Join(tempDir, "records.csv")
+		os.WriteFile(path, []byte("name,port\napp,8080\n"), 0600)
+		var got [][]string
+		if err := fileio.ReadAuto(path, &got); err != nil {
+			t.Fatalf("ReadAuto() unexpected error = %v", err)
+		}
+		if want := [][]string{{"name", "port"}, {"app", "8080"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadAuto() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{"JSON", "data.json", ".json", ""},
+		{"YAML", "dir/config.yaml", ".yaml", ""},
+		{"YML", "config.yml", ".yml", ""},
+		{"XML", "feed.xml", ".xml", ""},
+		{"CSV", "records.csv", ".csv", ""},
+		{"Uppercase", "DATA.JSON", "", `unsupported file extension ".JSON"`},
+		{"Unknown", "notes.txt", "", `unsupported file extension ".txt"`},
+		{"Empty", "", "", fileio.ErrEmptyPath.Error()},
+		{"Too long", strings.Repeat("a", 4097) + ".json", "", fileio.ErrPathTooLong.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fileio.DetectFormat(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("DetectFormat() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectFormat() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	mem := fileio.NewMemSerializer()
+	fileio.Register(".memtest", mem)
+	if err := mem.WriteFile(map[string]string{"key": "value"}, "data.memtest"); err != nil {
+		t.Fatalf("WriteFile() unexpected error = %v", err)
+	}
+	var got map[string]string
+	if err := fileio.ReadAuto("data.memtest", &got); err != nil {
+		t.Fatalf("ReadAuto() unexpected error = %v", err)
+	}
+	if got["key"] != "value" {
+		t.Errorf("ReadAuto() = %v, want key=value", got)
+	}
+	// A later registration takes over the extension
+	fileio.Register(".memtest", fileio.NewMemSerializer())
+	if err := fileio.ReadAuto("data.memtest", &got); !errors.Is(err, fileio.ErrFileNotExist) {
+		t.Errorf("ReadAuto() after replacing error = %v, want %v", err, fileio.ErrFileNotExist)
+	}
+	for _, ext := range []string{"", "json", "."} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", ext)
+				}
+			}()
+			fileio.Register(ext, mem)
+		}()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() with nil serializer did not panic")
+		}
+	}()
+	fileio.Register(".nil", nil)
+}
+
+func TestGet(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, ext := range []string{".json", ".yaml", ".yml", ".xml", ".csv"} {
+		t.Run(ext, func(t *testing.T) {
+			s, ok := fileio.Get(ext)
+			if !ok || s == nil {
+				t.Fatalf("Get(%q) = %v, %v, want registered serializer", ext, s, ok)
+			}
+			data := [][]string{{"name", "port"}, {"app", "8080"}}
+			path := filepath.Join(tempDir, "data"+ext)
+			if ext == ".xml" {
+				// XML needs a named root element rather than a bare slice
+				type record struct {
+					Name string `xml:"name"`
+				}
+				if err := s.WriteFile(record{Name: "app"}, path); err != nil {
+					t.Fatalf("WriteFile() unexpected error = %v", err)
+				}
+				var got record
+				if err := s.ReadFile(path, &got); err != nil || got.Name != "app" {
+					t.Errorf("ReadFile() = %+v, %v, want name app", got, err)
+				}
+				return
+			}
+			if err := s.WriteFile(data, path); err != nil {
+				t.Fatalf("WriteFile() unexpected error = %v", err)
+			}
+			var got [][]string
+			if err := s.ReadFile(path, &got); err != nil {
+				t.Fatalf("ReadFile() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, data) {
+				t.Errorf("ReadFile() = %v, want %v", got, data)
+			}
+		})
+	}
+
+	t.Run("Unregistered", func(t *testing.T) {
+		if s, ok := fileio.Get(".toml"); ok || s != nil {
+			t.Errorf("Get(%q) = %v, %v, want nil, false", ".toml", s, ok)
+		}
+	})
+
+	t.Run("Extensions", func(t *testing.T) {
+		exts := fileio.Extensions()
+		for _, ext := range []string{".csv", ".json", ".xml", ".yaml", ".yml"} {
+			if !slices.Contains(exts, ext) {
+				t.Errorf("Extensions() = %v, missing %q", exts, ext)
+			}
+		}
+		if !slices.IsSorted(exts) {
+			t.Errorf("Extensions() = %v, want sorted", exts)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package filesystem
+
+import "os"
+
+// fileIDOf reports false, as the device and inode number are not available from os.FileInfo on this platform.
+func fileIDOf(os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
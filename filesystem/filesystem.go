@@ -1,22 +1,35 @@
 // Package filesystem provides utilities for file and directory operations, MIME type detection, and filename sanitization.
 //
 // This package offers helper functions for managing files and directories, checking file existence,
-// appending content to files, creating files and directories, and sanitizing filenames for cross-platform compatibility.
+// appending content to files, creating files, directories and temporary files, and sanitizing filenames for cross-platform compatibility.
 // It also includes functions for determining MIME types based on file extensions or content.
 // These utilities are designed to be used within the devify-utils library to support robust file operations.
 package filesystem
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/fs"
+	"math"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf16"
 )
 
 // CaseStyle defines the style of the filename case.
@@ -76,6 +89,69 @@ func AppendToFile(path, content string) error {
 	return err
 }
 
+// WriteFileAtomic writes data to a file at the specified path so that readers never observe a partially written file.
+//
+// The data is written to a temporary file in the same directory, flushed to stable storage with fsync, given the
+// requested permissions, and then renamed over path. Because the rename happens within a single directory, it
+// replaces any existing file atomically on POSIX systems. Parent directories are created with CreateDirIfNotExist
+// if needed. The temporary file is removed if any step fails.
+//
+// Example:
+//
+//	err := WriteFileAtomic("config.json", []byte(`{"debug":true}`), 0o644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: The file path to write.
+//   - data: The content to write to the file.
+//   - perm: The file permission mode (os.FileMode) of the written file.
+//
+// Returns:
+//   - error: An error if the path is empty, too long, is a directory, or writing, syncing, or renaming fails.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	if path == "" || path == "." {
+		return errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return errors.New("path too long")
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("path %s is a directory, not a file", path)
+	}
+	dir := filepath.Dir(path)
+	if dir != "." {
+		if err := CreateDirIfNotExist(dir); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
 // CreateDirIfNotExist creates a directory at the specified path if it does not already exist.
 //
 // The function checks if the path is valid, not empty, and not too long (max 4096 characters).
@@ -119,6 +195,42 @@ func CreateDirIfNotExist(path string, perm ...os.FileMode) error {
 	return os.MkdirAll(path, fileMode)
 }
 
+// CreateDirSafe creates the directory rel under base if it does not already exist, rejecting paths that escape base.
+//
+// The function joins rel onto base and cleans the result with filepath.Clean, then verifies that it is still inside
+// base. Absolute rel paths and paths whose ".." segments climb out of base return an error before anything is
+// created, so rel can be derived from user input such as upload destinations. The check is lexical: symlinks that
+// already exist inside base are not resolved. The directory is then created with CreateDirIfNotExist.
+//
+// Example:
+//
+//	err := CreateDirSafe("uploads", userID+"/avatars", 0o750)
+//	if err != nil {
+//	    log.Fatal(err) // e.g., path "../etc" escapes base directory "uploads"
+//	}
+//
+// Parameters:
+//   - base: The directory that the created directory must stay within.
+//   - rel: The directory path to create, relative to base.
+//   - perm: Optional directory permission mode (os.FileMode). Defaults to 0755 if not provided.
+//
+// Returns:
+//   - error: An error if base is empty, rel is absolute or escapes base, or directory creation fails.
+func CreateDirSafe(base, rel string, perm ...os.FileMode) error {
+	if base == "" {
+		return errors.New("base path cannot be empty")
+	}
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return fmt.Errorf("path %q must be relative to base directory %q", rel, base)
+	}
+	path := filepath.Join(base, rel)
+	within, err := filepath.Rel(filepath.Clean(base), path)
+	if err != nil || within == ".." || strings.HasPrefix(within, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %q escapes base directory %q", rel, base)
+	}
+	return CreateDirIfNotExist(path, perm...)
+}
+
 // CreateFileIfNotExist creates a file at the specified path if it does not already exist.
 //
 // The function checks if the path is valid, not empty, and not too long (max 4096 characters).
@@ -166,6 +278,210 @@ func CreateFileIfNotExist(path string, perm ...os.FileMode) error {
 	return file.Close()
 }
 
+// Touch updates the access and modification times of a file, creating it if it does not exist.
+//
+// Like the Unix touch command, an existing file or directory keeps its content and only has its timestamps
+// updated, using os.Chtimes so it works across platforms. A missing file is created empty with CreateFileIfNotExist
+// (mode 0600), but missing parent directories are not created. The timestamps are set to the current time, or to
+// the time given as the optional argument (e.g., to backdate a file in build tooling or tests).
+//
+// Example:
+//
+//	err := Touch("cache/.stamp")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = Touch("old.log", time.Now().Add(-48*time.Hour)) // Backdates the file by two days
+//
+// Parameters:
+//   - path: The path of the file to touch.
+//   - t: Optional time to set as the access and modification time. Defaults to the current time if not provided.
+//
+// Returns:
+//   - error: An error if the path is empty or too long, the file cannot be created, or its times cannot be updated.
+func Touch(path string, t ...time.Time) error {
+	if path == "" || path == "." {
+		return errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return errors.New("path too long")
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := CreateFileIfNotExist(path); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	now := time.Now()
+	if len(t) > 0 {
+		now = t[0]
+	}
+	return os.Chtimes(path, now, now)
+}
+
+// TempFile creates a new temporary file in dir and returns it together with a cleanup function.
+//
+// The file is created with os.CreateTemp, so pattern works the same way: a random string replaces the last "*" in
+// pattern, or is appended if there is none. If dir is empty, os.TempDir is used; otherwise dir is created with
+// CreateDirIfNotExist if it does not exist. The cleanup function closes and removes the file, so callers can
+// `defer cleanup()` to avoid leaking temporary files. It is safe to call more than once and ignores a file that
+// has already been closed or removed.
+//
+// Example:
+//
+//	file, cleanup, err := TempFile("uploads/tmp", "upload-*.bin")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer cleanup()
+//	fmt.Println(file.Name()) // Prints the path of the temporary file, e.g., "uploads/tmp/upload-123456789.bin"
+//
+// Parameters:
+//   - dir: The directory in which to create the file. Defaults to os.TempDir() if empty.
+//   - pattern: The file name pattern, where the last "*" is replaced by a random string.
+//
+// Returns:
+//   - *os.File: The open temporary file.
+//   - func() error: A cleanup function that closes and removes the file.
+//   - error: An error if dir cannot be created or the file cannot be created.
+func TempFile(dir, pattern string) (*os.File, func() error, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	} else if dir != "." {
+		if err := CreateDirIfNotExist(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() error {
+		if err := file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			return err
+		}
+		if err := os.Remove(file.Name()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return file, cleanup, nil
+}
+
+// RemoveFileIfExists removes the file at the specified path if it exists.
+//
+// The function checks if the path is valid, not empty, and not too long (max 4096 characters).
+// If the path does not exist, no action is taken and (false, nil) is returned, including when the file
+// disappears between the check and the removal. If it exists as a directory, an error is returned.
+//
+// Example:
+//
+//	removed, err := RemoveFileIfExists("cache.tmp")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(removed) // Prints true if the file was deleted
+//
+// Parameters:
+//   - path: The file path to remove.
+//
+// Returns:
+//   - bool: True if a file was deleted, false if none existed.
+//   - error: An error if the path is empty, too long, is a directory, or file removal fails.
+func RemoveFileIfExists(path string) (bool, error) {
+	if path == "" || path == "." {
+		return false, errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return false, errors.New("path too long")
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if info.IsDir() {
+		return false, fmt.Errorf("path %s is a directory, not a file", path)
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CopyFile copies the file at src to a new file at dst, streaming the content rather than loading it into memory.
+//
+// Parent directories of dst are created with CreateDirIfNotExist if needed. If no permissions are provided, the
+// permission bits of src are preserved. The copy never overwrites: an error is returned if dst already exists.
+// If the copy fails part way, the partially written dst is removed.
+//
+// Example:
+//
+//	err := CopyFile("config.yaml", "backup/config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - src: The path of the file to copy.
+//   - dst: The path of the new file to create.
+//   - perm: Optional file permission mode (os.FileMode) for dst. Defaults to the mode of src if not provided.
+//
+// Returns:
+//   - error: An error if either path is empty or too long, src does not exist or is a directory,
+//     dst already exists, or the copy fails.
+func CopyFile(src, dst string, perm ...os.FileMode) error {
+	for _, path := range []string{src, dst} {
+		if path == "" || path == "." {
+			return errors.New("path cannot be empty or root")
+		}
+		if len(path) > 4096 {
+			return errors.New("path too long")
+		}
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("path %s is a directory, not a file", src)
+	}
+	fileMode := info.Mode().Perm()
+	if len(perm) > 0 {
+		fileMode = perm[0]
+	}
+	if dir := filepath.Dir(dst); dir != "." {
+		if err := CreateDirIfNotExist(dir); err != nil {
+			return err
+		}
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
 // GetMimeTypeFromExtension returns the MIME type for a given file extension.
 //
 // If the extension does not start with a dot, it is added automatically. If no MIME type is found,
@@ -193,49 +509,818 @@ func GetMimeTypeFromExtension(ext string) string {
 }
 
 // GetMimeTypeFromContent determines the MIME type of a file based on its content.
-// It reads the first 512 bytes of the file and uses http.DetectContentType to identify the MIME type.
-// If the file cannot be opened or read, an error is returned.
-// For empty files, it returns "application/octet-stream" with no error.
+//
+// The function reads up to the first 512 bytes of the file using ReadHead and uses http.DetectContentType
+// to identify the MIME type. For empty files, it returns "application/octet-stream" with no error.
+// If the path is invalid or the file cannot be opened or read, an error is returned.
 //
 // Example:
-// mimeType, err := GetMimeTypeFromContent("image.png")
 //
+//	mimeType, err := GetMimeTypeFromContent("image.png")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-//
-// fmt.Println(mimeType) // Prints "image/png"
+//	fmt.Println(mimeType) // Prints "image/png"
 //
 // Parameters:
-// - path: The file path to analyze.
+//   - path: The file path to analyze.
 //
 // Returns:
-// - string: The detected MIME type (e.g., "image/png", "text/plain; charset=utf-8").
-// - error: An error if the file cannot be opened or read.
+//   - string: The detected MIME type (e.g., "image/png", "text/plain; charset=utf-8").
+//   - error: An error if the path is empty, too long, or the file cannot be opened or read.
 func GetMimeTypeFromContent(path string) (string, error) {
+	head, err := ReadHead(path, 512)
+	if err != nil {
+		return "", err
+	}
+	if len(head) == 0 {
+		return "application/octet-stream", nil
+	}
+	return http.DetectContentType(head), nil
+}
+
+// ReadHead reads up to the first n bytes of a file without reading the remainder.
+//
+// The function checks if the path is valid, not empty, and not too long (max 4096 characters), and that n is
+// non-negative. If the file is shorter than n bytes, only the available bytes are returned without an error.
+// This is useful for format sniffing or previews of large files.
+//
+// Example:
+//
+//	head, err := ReadHead("large.log", 64)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(head)) // Prints the first 64 bytes of the file
+//
+// Parameters:
+//   - path: The file path to read from.
+//   - n: The maximum number of bytes to read.
+//
+// Returns:
+//   - []byte: The first n bytes of the file, or fewer if the file is shorter.
+//   - error: An error if the path is invalid, n is negative, or the file cannot be opened or read.
+func ReadHead(path string, n int) ([]byte, error) {
+	if path == "" || path == "." {
+		return nil, errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return nil, errors.New("path too long")
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("n must be non-negative, got %d", n)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	// Read through a limit rather than allocating n bytes up front, as n may be far larger than the file
+	buffer, err := io.ReadAll(io.LimitReader(file, int64(n)))
+	if err != nil {
+		return nil, err
+	}
+	return buffer, nil
+}
+
+// FileHash computes the checksum of a file's content using the named hash algorithm.
+//
+// Supported algorithms are "md5", "sha1", and "sha256" (case-insensitive); an empty algo defaults to "sha256".
+// The file is streamed through the hash, so large files are never fully loaded into memory. MD5 and SHA-1 are
+// provided for compatibility with existing checksums and should not be relied on for security.
+//
+// Example:
+//
+//	sum, err := FileHash("upload.bin", "sha256")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(sum) // Prints the lowercase hex digest, e.g., "9f86d081884c7d65..."
+//
+// Parameters:
+//   - path: The file path to hash.
+//   - algo: The hash algorithm ("md5", "sha1", or "sha256"). Defaults to "sha256" if empty.
+//
+// Returns:
+//   - string: The lowercase hexadecimal digest.
+//   - error: An error if the path is empty, too long, a directory, the algorithm is unknown, or the file cannot be read.
+func FileHash(path string, algo string) (string, error) {
 	if path == "" || path == "." {
 		return "", errors.New("path cannot be empty or root")
 	}
 	if len(path) > 4096 {
 		return "", errors.New("path too long")
 	}
+	var h hash.Hash
+	switch strings.ToLower(algo) {
+	case "", "sha256":
+		h = sha256.New()
+	case "sha1":
+		h = sha1.New()
+	case "md5":
+		h = md5.New()
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm: %s", algo)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("path %s is a directory, not a file", path)
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
-	buffer := make([]byte, 512)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
+	if _, err := io.Copy(h, file); err != nil {
 		return "", err
 	}
-	if n == 0 {
-		return "application/octet-stream", nil
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ETag computes an HTTP entity tag for a file, suitable for use in the ETag response header.
+//
+// By default a weak ETag (e.g., W/"1a-17f3c2b1e8a4d000") is derived from the file's size and modification time.
+// Weak ETags are cheap to compute but only indicate semantic equivalence: a file rewritten with identical content
+// gets a new tag, and a change that preserves both size and modification time goes unnoticed.
+// If strong is true, a strong ETag (e.g., "9f86d0...") is derived from the SHA-256 hash of the file content,
+// which guarantees byte-for-byte equality at the cost of reading the whole file.
+// The returned value is quoted as required by RFC 9110.
+//
+// Example:
+//
+//	tag, err := ETag("static/app.js")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	w.Header().Set("ETag", tag)
+//
+// Parameters:
+//   - path: The file path to compute the ETag for.
+//   - strong: Optional boolean indicating if a strong, content-based ETag is required (defaults to false).
+//
+// Returns:
+//   - string: The quoted ETag, prefixed with W/ if weak.
+//   - error: An error if the path is empty, too long, a directory, or the file cannot be read.
+func ETag(path string, strong ...bool) (string, error) {
+	if path == "" || path == "." {
+		return "", errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return "", errors.New("path too long")
 	}
-	mimeType := http.DetectContentType(buffer[:n])
-	return mimeType, nil
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("path %s is a directory, not a file", path)
+	}
+	if len(strong) == 0 || !strong[0] {
+		return fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano()), nil
+	}
+	sum, err := FileHash(path, "sha256")
+	if err != nil {
+		return "", err
+	}
+	return `"` + sum + `"`, nil
 }
 
+// VerifyLayout checks that a directory contains a set of required files and subdirectories.
+//
+// Each entry in required is a slash-separated path relative to root. Entries ending with "/" are expected to be
+// directories; all other entries are expected to be files. An entry is reported as missing if it does not exist
+// or if its type does not match the expectation (e.g., a file was expected but a directory was found).
+// Missing entries are returned in the order they appear in required. An error is returned only if root is
+// invalid or an entry cannot be inspected.
+//
+// Example:
+//
+//	missing, err := VerifyLayout("project", []string{"go.mod", "cmd/", "internal/"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(missing) // Prints the entries that are absent or of the wrong type, e.g., [internal/]
+//
+// Parameters:
+//   - root: The directory to verify.
+//   - required: The relative paths that must exist under root, with a trailing "/" for directories.
+//
+// Returns:
+//   - []string: The required entries that are missing or of the wrong type.
+//   - error: An error if root is empty, too long, not a directory, or an entry cannot be inspected.
+func VerifyLayout(root string, required []string) (missing []string, err error) {
+	if root == "" {
+		return nil, errors.New("path cannot be empty or root")
+	}
+	if len(root) > 4096 {
+		return nil, errors.New("path too long")
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path %s is a file, not a directory", root)
+	}
+	for _, entry := range required {
+		wantDir := strings.HasSuffix(entry, "/")
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(entry)))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			missing = append(missing, entry)
+			continue
+		}
+		if info.IsDir() != wantDir {
+			missing = append(missing, entry)
+		}
+	}
+	return missing, nil
+}
+
+// ReadLines reads a text file and returns its lines with trailing "\n" or "\r\n" line endings stripped.
+//
+// The file is read with a bufio.Scanner, so lines are processed without holding more than one line in the scanner's
+// buffer. By default lines may be up to 1 MiB long; a different maximum can be provided for files with longer lines.
+// An error is returned if a line exceeds the maximum length.
+//
+// Example:
+//
+//	lines, err := ReadLines("app.log")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(len(lines)) // Prints the number of lines in the file
+//
+// Parameters:
+//   - path: The file path to read.
+//   - maxLineLength: Optional maximum line length in bytes. Defaults to 1 MiB if not provided.
+//
+// Returns:
+//   - []string: The lines of the file, without line endings.
+//   - error: An error if the path is empty, too long, a directory, maxLineLength is not positive,
+//     a line is too long, or the file cannot be read.
+func ReadLines(path string, maxLineLength ...int) ([]string, error) {
+	if path == "" || path == "." {
+		return nil, errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return nil, errors.New("path too long")
+	}
+	maxLen := 1 << 20
+	if len(maxLineLength) > 0 {
+		maxLen = maxLineLength[0]
+	}
+	if maxLen <= 0 {
+		return nil, fmt.Errorf("max line length must be positive, got %d", maxLen)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("path %s is a directory, not a file", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	// Allow room for the line ending on top of the maximum line length, without overflowing for huge maximums
+	maxLen = min(maxLen, math.MaxInt-2)
+	scanner.Buffer(make([]byte, 0, min(maxLen+2, 64*1024)), maxLen+2)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// ReadTextFile reads a text file and returns its content as a UTF-8 string, handling a leading byte order mark (BOM).
+//
+// A UTF-8 BOM is stripped. UTF-16 little-endian and big-endian BOMs are stripped and the remaining content
+// is transcoded to UTF-8. Files without a BOM are returned as-is. This is useful for reading files exported
+// by tools (commonly on Windows) that prepend a BOM which would otherwise corrupt parsing.
+//
+// Example:
+//
+//	text, err := ReadTextFile("export.txt")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(text) // Prints the file content without a BOM
+//
+// Parameters:
+//   - path: The file path to read.
+//
+// Returns:
+//   - string: The file content as a UTF-8 string.
+//   - error: An error if the path is empty, too long, the file cannot be read, or UTF-16 content has an odd byte length.
+func ReadTextFile(path string) (string, error) {
+	if path == "" || path == "." {
+		return "", errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return "", errors.New("path too long")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:]), nil
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], binary.LittleEndian)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], binary.BigEndian)
+	}
+	return string(data), nil
+}
+
+// decodeUTF16 transcodes UTF-16 data in the given byte order to a UTF-8 string.
+func decodeUTF16(data []byte, order binary.ByteOrder) (string, error) {
+	if len(data)%2 != 0 {
+		return "", errors.New("invalid UTF-16 content: odd byte length")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// IsEmpty reports whether a file has no content or a directory has no entries.
+//
+// A regular file is empty if its size is zero. For a directory, at most one entry is read, so the check is cheap
+// even for large directories. Symlinks are followed. An error is returned if the path does not exist, rather than
+// reporting it as empty, so that a typo cannot be mistaken for an empty directory.
+//
+// Example:
+//
+//	empty, err := IsEmpty("tmp/uploads")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if empty {
+//	    os.Remove("tmp/uploads")
+//	}
+//
+// Parameters:
+//   - path: The path of the file or directory to check.
+//
+// Returns:
+//   - bool: True if the file has zero bytes or the directory has no entries.
+//   - error: An error if the path is empty, too long, does not exist, or cannot be read.
+func IsEmpty(path string) (bool, error) {
+	if path == "" {
+		return false, errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return false, errors.New("path too long")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return info.Size() == 0, nil
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+	if _, err := dir.Readdirnames(1); err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// FindStale walks a directory recursively and returns the regular files last modified more than olderThan ago.
+//
+// A file is stale if its modification time is before time.Now().Add(-olderThan). Directories, symlinks, and other
+// non-regular files are skipped. Paths are returned in lexical walk order, joined with dir. An empty directory or one
+// without stale files yields an empty result and no error. This is useful for cleanup jobs and cache eviction.
+//
+// Example:
+//
+//	stale, err := FindStale("tmp/uploads", 24*time.Hour)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, path := range stale {
+//	    os.Remove(path)
+//	}
+//
+// Parameters:
+//   - dir: The directory to search.
+//   - olderThan: The minimum age of files to return (must be non-negative).
+//
+// Returns:
+//   - []string: The paths of stale regular files.
+//   - error: An error if dir is empty, too long, not a directory, olderThan is negative, or the walk fails.
+func FindStale(dir string, olderThan time.Duration) ([]string, error) {
+	if dir == "" {
+		return nil, errors.New("path cannot be empty or root")
+	}
+	if len(dir) > 4096 {
+		return nil, errors.New("path too long")
+	}
+	if olderThan < 0 {
+		return nil, fmt.Errorf("olderThan must be non-negative, got %v", olderThan)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path %s is a file, not a directory", dir)
+	}
+	cutoff := time.Now().Add(-olderThan)
+	var stale []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(cutoff) {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stale, nil
+}
+
+// DirSize walks a directory recursively and returns the total size in bytes of the regular files it contains.
+//
+// Symlinks are not followed and do not contribute to the total, which avoids counting the same data twice and
+// prevents infinite loops on symlink cycles. Directories and other non-regular files are skipped. An empty
+// directory yields a size of 0. This is useful for enforcing quotas on upload or cache directories.
+//
+// Example:
+//
+//	size, err := DirSize("uploads")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(size) // Prints the total size in bytes, e.g., 1048576
+//
+// Parameters:
+//   - path: The directory to measure.
+//
+// Returns:
+//   - int64: The total size in bytes of all regular files under path.
+//   - error: An error if path is empty, too long, does not exist, is not a directory, or the walk fails.
+func DirSize(path string) (int64, error) {
+	if path == "" {
+		return 0, errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return 0, errors.New("path too long")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("path %s is a file, not a directory", path)
+	}
+	var total int64
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// ListFiles returns the paths of the regular files in a directory whose names match a pattern.
+//
+// The pattern uses filepath.Match semantics and is matched against each file's base name (e.g., "*.csv" or
+// "report-??.json"); an empty pattern matches every file. By default only the files directly inside dir are listed;
+// pass recursive as true to descend into subdirectories. Directories are never returned, even if their names match,
+// and symlinks are neither returned nor followed. Paths are returned in lexical order, joined with dir.
+// If nothing matches, an empty slice and no error are returned.
+//
+// Example:
+//
+//	files, err := ListFiles("imports", "*.csv", true)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, path := range files {
+//	    fmt.Println(path) // Prints each CSV file found under imports
+//	}
+//
+// Parameters:
+//   - dir: The directory to search.
+//   - pattern: The filepath.Match pattern applied to file names.
+//   - recursive: Optional boolean indicating if subdirectories should be searched (defaults to false).
+//
+// Returns:
+//   - []string: The paths of the matching regular files.
+//   - error: An error if dir is empty, too long, not a directory, the pattern is malformed, or the walk fails.
+func ListFiles(dir string, pattern string, recursive ...bool) ([]string, error) {
+	if dir == "" {
+		return nil, errors.New("path cannot be empty or root")
+	}
+	if len(dir) > 4096 {
+		return nil, errors.New("path too long")
+	}
+	if pattern == "" {
+		pattern = "*"
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path %s is a file, not a directory", dir)
+	}
+	deep := len(recursive) > 0 && recursive[0]
+	files := []string{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !deep {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if matched, _ := filepath.Match(pattern, d.Name()); matched {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// WalkSafe walks the file tree rooted at root like filepath.WalkDir, but also follows symlinks to directories
+// while guarding against cycles.
+//
+// Entries are visited in lexical order and fn is called for each file and directory, with the same handling of errors,
+// filepath.SkipDir, and filepath.SkipAll as filepath.WalkDir. A symlink that points to a directory is reported to fn as
+// a directory (its fs.DirEntry describes the target) and descended into. Every directory is identified by its device
+// and inode number (falling back to os.SameFile where they are unavailable); when a directory that has already been
+// visited is reached again, whether through a symlink cycle or a second link to the same directory, it is skipped
+// silently: fn is not called for it and its contents are not walked again. This guarantees termination on circular
+// symlinks. Symlinks to files and broken symlinks are reported as-is and not followed.
+//
+// Example:
+//
+//	err := WalkSafe("shared", func(path string, d fs.DirEntry, err error) error {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(path)
+//	    return nil
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - root: The directory (or file) to walk.
+//   - fn: The function called for each visited file and directory.
+//
+// Returns:
+//   - error: The first error returned by fn, other than filepath.SkipDir or filepath.SkipAll.
+func WalkSafe(root string, fn fs.WalkDirFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		visited := &visitedDirs{ids: make(map[fileID]bool)}
+		err = walkSafe(root, fs.FileInfoToDirEntry(info), fn, visited)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// fileID identifies a file by its device and inode number.
+type fileID struct {
+	dev, ino uint64
+}
+
+// visitedDirs records the directories walked by WalkSafe. Directories are keyed by fileID in ids; infos holds the
+// directories whose fileID is unavailable on this platform, which are compared with os.SameFile instead.
+type visitedDirs struct {
+	ids   map[fileID]bool
+	infos []os.FileInfo
+}
+
+// visit records the directory described by info and reports whether it had already been visited.
+func (v *visitedDirs) visit(info os.FileInfo) bool {
+	if id, ok := fileIDOf(info); ok {
+		if v.ids[id] {
+			return true
+		}
+		v.ids[id] = true
+		return false
+	}
+	for _, seen := range v.infos {
+		if os.SameFile(seen, info) {
+			return true
+		}
+	}
+	v.infos = append(v.infos, info)
+	return false
+}
+
+// walkSafe recursively walks path for WalkSafe, recording each directory in visited.
+func walkSafe(path string, d fs.DirEntry, fn fs.WalkDirFunc, visited *visitedDirs) error {
+	if !d.IsDir() {
+		return fn(path, d, nil)
+	}
+	info, err := d.Info()
+	if err != nil {
+		return fn(path, d, err)
+	}
+	if visited.visit(info) {
+		return nil
+	}
+	if err := fn(path, d, nil); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if err := fn(path, d, err); err != nil {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+	}
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if target, err := os.Stat(child); err == nil && target.IsDir() {
+				entry = fs.FileInfoToDirEntry(target)
+			}
+		}
+		if err := walkSafe(child, entry, fn, visited); err != nil {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// HumanSize formats a byte count as a human-readable string, such as "10.5 MiB" or "512 B".
+//
+// By default, binary units (KiB, MiB, GiB, TiB, PiB, EiB; powers of 1024) are used. Pass decimal as true to use
+// decimal units instead (KB, MB, GB, TB, PB, EB; powers of 1000). Values of at least one unit are shown with one
+// decimal place, omitted when it is zero (e.g., "10 MiB"). Negative values are formatted with a leading "-".
+//
+// Example:
+//
+//	fmt.Println(HumanSize(11010048))       // Prints "10.5 MiB"
+//	fmt.Println(HumanSize(11010048, true)) // Prints "11 MB"
+//
+// Parameters:
+//   - bytes: The number of bytes to format.
+//   - decimal: Optional boolean indicating if decimal (SI) units should be used (defaults to false).
+//
+// Returns:
+//   - string: The formatted size.
+func HumanSize(bytes int64, decimal ...bool) string {
+	base, units := 1024.0, binarySizeUnits
+	if len(decimal) > 0 && decimal[0] {
+		base, units = 1000.0, decimalSizeUnits
+	}
+	sign := ""
+	value := float64(bytes)
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	if value < base {
+		return fmt.Sprintf("%s%d B", sign, int64(value))
+	}
+	unit := -1
+	for value >= base && unit < len(units)-1 {
+		value /= base
+		unit++
+	}
+	formatted := strconv.FormatFloat(value, 'f', 1, 64)
+	if value >= base-0.05 && unit < len(units)-1 {
+		// Rounding would print e.g. "1024.0 KiB"; promote to the next unit instead.
+		value /= base
+		unit++
+		formatted = strconv.FormatFloat(value, 'f', 1, 64)
+	}
+	return sign + strings.TrimSuffix(formatted, ".0") + " " + units[unit]
+}
+
+// ParseSize parses a human-readable size, such as "10MB" or "1.5 GiB", into a number of bytes.
+//
+// The input is a non-negative number, optionally fractional, followed by an optional unit separated by optional
+// whitespace. Units are case-insensitive: "B" for bytes, decimal units "KB", "MB", "GB", "TB", "PB", and "EB"
+// (powers of 1000), and binary units "KiB", "MiB", "GiB", "TiB", "PiB", and "EiB" (powers of 1024). A number
+// without a unit is a count of bytes. Fractional results are truncated to whole bytes. This is the reverse of
+// HumanSize and allows configuration values like MaxFileSize: "10MB".
+//
+// Example:
+//
+//	size, err := ParseSize("1.5GiB")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(size) // Prints 1610612736
+//
+// Parameters:
+//   - s: The size string to parse.
+//
+// Returns:
+//   - int64: The size in bytes.
+//   - error: An error if the string is empty, the number or unit is invalid, or the size overflows int64.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("size cannot be empty")
+	}
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := s, ""
+	if i >= 0 {
+		number, unit = s[:i], strings.TrimSpace(s[i:])
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || number == "" {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	multiplier, ok := sizeMultipliers[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit %q in %q", unit, s)
+	}
+	size := value * multiplier
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q overflows int64", s)
+	}
+	return int64(size), nil
+}
+
+var (
+	binarySizeUnits  = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	decimalSizeUnits = []string{"KB", "MB", "GB", "TB", "PB", "EB"}
+	// sizeMultipliers maps lowercase unit suffixes accepted by ParseSize to their size in bytes.
+	sizeMultipliers = map[string]float64{
+		"": 1, "b": 1,
+		"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12, "pb": 1e15, "eb": 1e18,
+		"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40, "pib": 1 << 50, "eib": 1 << 60,
+	}
+)
+
 // SanitizeFilename sanitizes a filename to ensure it is safe for use across Linux, macOS, and Windows.
 //
 // The function removes or replaces invalid characters, non-printable characters, and control characters,
@@ -1,11 +1,19 @@
 package filesystem_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/devify-me/devify-utils/filesystem"
 )
@@ -77,6 +85,95 @@ func TestAppendToFile(t *testing.T) {
 	}
 }
 
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+	existingPath := filepath.Join(tempDir, "existing.txt")
+	os.WriteFile(existingPath, []byte("old content that is longer"), 0600)
+	longPath := filepath.Join(tempDir, string(make([]rune, 4097)))
+
+	tests := []struct {
+		name    string
+		path    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Path too long",
+			path:    longPath,
+			data:    []byte("x"),
+			wantErr: "path too long",
+		},
+		{
+			name:    "Path is directory",
+			path:    dirPath,
+			data:    []byte("x"),
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name: "New file",
+			path: filepath.Join(tempDir, "new.txt"),
+			data: []byte("hello"),
+		},
+		{
+			name: "Replace existing file",
+			path: existingPath,
+			data: []byte("new"),
+		},
+		{
+			name: "Create parent directories",
+			path: filepath.Join(tempDir, "a", "b", "c.json"),
+			data: []byte(`{"ok":true}`),
+		},
+		{
+			name: "Empty data",
+			path: filepath.Join(tempDir, "empty.txt"),
+			data: []byte{},
+		},
+		{
+			name: "Bare relative filename",
+			path: "config.json",
+			data: []byte(`{"debug":true}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := filesystem.WriteFileAtomic(tt.path, tt.data, 0640)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("WriteFileAtomic() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteFileAtomic() unexpected error = %v", err)
+			}
+			got, err := os.ReadFile(tt.path)
+			if err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("WriteFileAtomic() content = %q, %v, want %q", got, err, tt.data)
+			}
+			info, _ := os.Stat(tt.path)
+			if info.Mode().Perm() != 0640 {
+				t.Errorf("WriteFileAtomic() mode = %v, want %v", info.Mode().Perm(), os.FileMode(0640))
+			}
+			entries, _ := os.ReadDir(filepath.Dir(tt.path))
+			for _, e := range entries {
+				if strings.Contains(e.Name(), ".tmp-") {
+					t.Errorf("WriteFileAtomic() left temp file %s", e.Name())
+				}
+			}
+		})
+	}
+}
+
 func TestCreateDirIfNotExist(t *testing.T) {
 	tempDir := t.TempDir()
 	validPath := filepath.Join(tempDir, "newdir")
@@ -150,6 +247,96 @@ func TestCreateDirIfNotExist(t *testing.T) {
 	}
 }
 
+func TestCreateDirSafe(t *testing.T) {
+	base := t.TempDir()
+	filePath := filepath.Join(base, "file.txt")
+	os.WriteFile(filePath, []byte("x"), 0600)
+
+	tests := []struct {
+		name    string
+		base    string
+		rel     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Nested directory",
+			base: base,
+			rel:  "user/avatars",
+			want: filepath.Join(base, "user", "avatars"),
+		},
+		{
+			name: "Dot segments staying inside",
+			base: base,
+			rel:  "user/../other/./dir",
+			want: filepath.Join(base, "other", "dir"),
+		},
+		{
+			name: "Name starting with dots",
+			base: base,
+			rel:  "..hidden",
+			want: filepath.Join(base, "..hidden"),
+		},
+		{
+			name:    "Parent escape",
+			base:    base,
+			rel:     "../outside",
+			wantErr: "escapes base directory",
+		},
+		{
+			name:    "Nested escape",
+			base:    base,
+			rel:     "user/../../outside",
+			wantErr: "escapes base directory",
+		},
+		{
+			name:    "Exactly parent",
+			base:    base,
+			rel:     "..",
+			wantErr: "escapes base directory",
+		},
+		{
+			name:    "Absolute path",
+			base:    base,
+			rel:     filepath.Join(base, "abs"),
+			wantErr: "must be relative to base directory",
+		},
+		{
+			name:    "Empty base",
+			base:    "",
+			rel:     "dir",
+			wantErr: "base path cannot be empty",
+		},
+		{
+			name:    "Existing file",
+			base:    base,
+			rel:     "file.txt",
+			wantErr: "is a file, not a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := filesystem.CreateDirSafe(tt.base, tt.rel)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("CreateDirSafe() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateDirSafe() unexpected error = %v", err)
+			}
+			if info, err := os.Stat(tt.want); err != nil || !info.IsDir() {
+				t.Errorf("CreateDirSafe() did not create %s", tt.want)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(base), "outside")); !os.IsNotExist(err) {
+		t.Errorf("CreateDirSafe() created a directory outside base")
+	}
+}
+
 func TestCreateFileIfNotExist(t *testing.T) {
 	tempDir := t.TempDir()
 	validPath := filepath.Join(tempDir, "newfile.txt")
@@ -223,145 +410,1404 @@ func TestCreateFileIfNotExist(t *testing.T) {
 	}
 }
 
-func TestGetMimeTypeFromExtension(t *testing.T) {
+func TestTouch(t *testing.T) {
+	tempDir := t.TempDir()
+	existingPath := filepath.Join(tempDir, "existing.txt")
+	os.WriteFile(existingPath, []byte("content"), 0600)
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(existingPath, old, old)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+	os.Chtimes(dirPath, old, old)
+	explicit := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
 	tests := []struct {
-		name string
-		ext  string
-		want string
+		name    string
+		path    string
+		t       []time.Time
+		want    time.Time // Zero means "about now"
+		wantErr string
 	}{
-		{
-			name: "With dot",
-			ext:  ".jpg",
-			want: "image/jpeg",
-		},
-		{
-			name: "Without dot",
-			ext:  "png",
-			want: "image/png",
-		},
-		{
-			name: "Unknown",
-			ext:  ".unknown",
-			want: "application/octet-stream",
-		},
-		{
-			name: "Empty",
-			ext:  "",
-			want: "application/octet-stream",
-		},
+		{name: "Create missing file", path: filepath.Join(tempDir, "new.txt")},
+		{name: "Update existing file", path: existingPath},
+		{name: "Update directory", path: dirPath},
+		{name: "Explicit time", path: filepath.Join(tempDir, "explicit.txt"), t: []time.Time{explicit}, want: explicit},
+		{name: "Empty path", path: "", wantErr: "path cannot be empty or root"},
+		{name: "Path too long", path: filepath.Join(tempDir, strings.Repeat("a", 4097)), wantErr: "path too long"},
+		{name: "Missing parent", path: filepath.Join(tempDir, "missing", "file.txt"), wantErr: "no such file or directory"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := filesystem.GetMimeTypeFromExtension(tt.ext); got != tt.want {
-				t.Errorf("GetMimeTypeFromExtension() = %v, want %v", got, tt.want)
+			before := time.Now().Add(-time.Second)
+			err := filesystem.Touch(tt.path, tt.t...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Touch() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Touch() unexpected error = %v", err)
+			}
+			info, err := os.Stat(tt.path)
+			if err != nil {
+				t.Fatalf("Touch() did not create %q: %v", tt.path, err)
+			}
+			if !tt.want.IsZero() {
+				if !info.ModTime().Equal(tt.want) {
+					t.Errorf("Touch() mtime = %v, want %v", info.ModTime(), tt.want)
+				}
+			} else if info.ModTime().Before(before) {
+				t.Errorf("Touch() mtime = %v, want after %v", info.ModTime(), before)
 			}
 		})
 	}
+
+	content, _ := os.ReadFile(existingPath)
+	if string(content) != "content" {
+		t.Errorf("Touch() changed file content to %q", content)
+	}
 }
 
-func TestGetMimeTypeFromContent(t *testing.T) {
+func TestTempFile(t *testing.T) {
 	tempDir := t.TempDir()
-	textPath := filepath.Join(tempDir, "text.txt")
-	imagePath := filepath.Join(tempDir, "image.png")
-	pdfPath := filepath.Join(tempDir, "document.pdf")
-	emptyPath := filepath.Join(tempDir, "empty.txt")
-	nonexistentPath := filepath.Join(tempDir, "nonexistent.txt")
-	longPath := filepath.Join(tempDir, string(make([]rune, 4097)))
-
-	// Setup test files
-	os.WriteFile(textPath, []byte("Hello, world!"), 0600)
-	// Minimal PNG header
-	os.WriteFile(imagePath, []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, 0600)
-	// Minimal PDF header
-	os.WriteFile(pdfPath, []byte("%PDF-1.4\n"), 0600)
-	os.WriteFile(emptyPath, []byte{}, 0600)
+	t.Chdir(tempDir)
+	filePath := filepath.Join(tempDir, "file.txt")
+	os.WriteFile(filePath, []byte("test"), 0600)
 
 	tests := []struct {
 		name    string
-		path    string
-		want    string
-		wantErr bool
+		dir     string
+		pattern string
+		wantDir string
+		wantErr string
 	}{
 		{
-			name:    "Text file",
-			path:    textPath,
-			want:    "text/plain; charset=utf-8",
-			wantErr: false,
+			name:    "Existing directory",
+			dir:     tempDir,
+			pattern: "upload-*.bin",
+			wantDir: tempDir,
 		},
 		{
-			name:    "Image file",
-			path:    imagePath,
-			want:    "image/png",
-			wantErr: false,
+			name:    "Missing directory is created",
+			dir:     filepath.Join(tempDir, "nested", "tmp"),
+			pattern: "data",
+			wantDir: filepath.Join(tempDir, "nested", "tmp"),
 		},
 		{
-			name:    "PDF file",
-			path:    pdfPath,
-			want:    "application/pdf",
-			wantErr: false,
+			name:    "Default directory",
+			dir:     "",
+			pattern: "devify-*",
+			wantDir: os.TempDir(),
 		},
 		{
-			name:    "Empty file",
-			path:    emptyPath,
-			want:    "application/octet-stream",
-			wantErr: false,
+			name:    "Current directory",
+			dir:     ".",
+			pattern: "x-*",
+			wantDir: ".",
 		},
 		{
-			name:    "Non-existent file",
-			path:    nonexistentPath,
-			want:    "",
-			wantErr: true,
+			name:    "Directory is a file",
+			dir:     filePath,
+			pattern: "x-*",
+			wantErr: "is a file, not a directory",
+		},
+		{
+			name:    "Pattern with separator",
+			dir:     tempDir,
+			pattern: "a/b-*",
+			wantErr: "pattern contains path separator",
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, cleanup, err := filesystem.TempFile(tt.dir, tt.pattern)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("TempFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TempFile() unexpected error = %v", err)
+			}
+			if filepath.Dir(file.Name()) != filepath.Clean(tt.wantDir) {
+				t.Errorf("TempFile() created %q, want in directory %q", file.Name(), tt.wantDir)
+			}
+			if _, err := file.WriteString("content"); err != nil {
+				t.Errorf("TempFile() file not writable: %v", err)
+			}
+			if err := cleanup(); err != nil {
+				t.Errorf("cleanup() unexpected error = %v", err)
+			}
+			if filesystem.FileExists(file.Name()) {
+				t.Errorf("cleanup() did not remove %q", file.Name())
+			}
+			if err := cleanup(); err != nil {
+				t.Errorf("cleanup() second call error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRemoveFileIfExists(t *testing.T) {
+	tempDir := t.TempDir()
+	validPath := filepath.Join(tempDir, "remove.txt")
+	os.WriteFile(validPath, []byte("data"), 0600)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+	longPath := filepath.Join(tempDir, string(make([]rune, 4097)))
+
+	tests := []struct {
+		name        string
+		path        string
+		wantRemoved bool
+		wantErr     string
+	}{
 		{
 			name:    "Empty path",
 			path:    "",
-			want:    "",
-			wantErr: true,
+			wantErr: "path cannot be empty or root",
 		},
 		{
 			name:    "Root path",
 			path:    ".",
-			want:    "",
-			wantErr: true,
+			wantErr: "path cannot be empty or root",
 		},
 		{
 			name:    "Path too long",
 			path:    longPath,
-			want:    "",
-			wantErr: true,
+			wantErr: "path too long",
+		},
+		{
+			name:    "Path is directory",
+			path:    dirPath,
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name:        "Existing file",
+			path:        validPath,
+			wantRemoved: true,
+		},
+		{
+			name:        "Already removed",
+			path:        validPath,
+			wantRemoved: false,
+		},
+		{
+			name:        "Nonexistent file",
+			path:        filepath.Join(tempDir, "missing", "file.txt"),
+			wantRemoved: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := filesystem.GetMimeTypeFromContent(tt.path)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetMimeTypeFromContent() error = %v, wantErr %v", err, tt.wantErr)
+			removed, err := filesystem.RemoveFileIfExists(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RemoveFileIfExists() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
 				return
 			}
-			if got != tt.want {
-				t.Errorf("GetMimeTypeFromContent() = %v, want %v", got, tt.want)
+			if err != nil {
+				t.Errorf("RemoveFileIfExists() unexpected error = %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("RemoveFileIfExists() = %v, want %v", removed, tt.wantRemoved)
+			}
+			if filesystem.FileExists(tt.path) {
+				t.Errorf("RemoveFileIfExists() file %s still exists", tt.path)
 			}
 		})
 	}
+	if _, err := os.Stat(dirPath); err != nil {
+		t.Errorf("RemoveFileIfExists() removed directory: %v", err)
+	}
 }
 
-func TestSanitizeFilename(t *testing.T) {
+func TestCopyFile(t *testing.T) {
+	tempDir := t.TempDir()
+	srcPath := filepath.Join(tempDir, "src.bin")
+	content := []byte(strings.Repeat("copy me\x00\xff", 10000))
+	os.WriteFile(srcPath, content, 0640)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+	existingPath := filepath.Join(tempDir, "existing.bin")
+	os.WriteFile(existingPath, []byte("keep"), 0600)
+
 	tests := []struct {
 		name     string
-		filename string
-		want     string
+		src      string
+		dst      string
+		perm     os.FileMode
+		wantMode os.FileMode
 		wantErr  string
 	}{
 		{
-			name:     "Empty filename",
-			filename: "",
-			wantErr:  "filename cannot be empty",
+			name:     "Copy preserving mode",
+			src:      srcPath,
+			dst:      filepath.Join(tempDir, "copy.bin"),
+			wantMode: 0640,
 		},
 		{
-			name:     "Invalid chars",
-			filename: "file/name:with?invalid*chars",
+			name:     "Copy into new nested dir",
+			src:      srcPath,
+			dst:      filepath.Join(tempDir, "a", "b", "copy.bin"),
+			wantMode: 0640,
+		},
+		{
+			name:     "Copy with custom perm",
+			src:      srcPath,
+			dst:      filepath.Join(tempDir, "custom.bin"),
+			perm:     0600,
+			wantMode: 0600,
+		},
+		{
+			name:    "Source does not exist",
+			src:     filepath.Join(tempDir, "nonexistent.bin"),
+			dst:     filepath.Join(tempDir, "out.bin"),
+			wantErr: "no such file",
+		},
+		{
+			name:    "Source is directory",
+			src:     dirPath,
+			dst:     filepath.Join(tempDir, "out.bin"),
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name:    "Destination exists",
+			src:     srcPath,
+			dst:     existingPath,
+			wantErr: "file exists",
+		},
+		{
+			name:    "Empty destination",
+			src:     srcPath,
+			dst:     "",
+			wantErr: "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var perm []os.FileMode
+			if tt.perm != 0 {
+				perm = []os.FileMode{tt.perm}
+			}
+			err := filesystem.CopyFile(tt.src, tt.dst, perm...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("CopyFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CopyFile() unexpected error = %v", err)
+			}
+			got, _ := os.ReadFile(tt.dst)
+			if !bytes.Equal(got, content) {
+				t.Errorf("CopyFile() content mismatch: got %d bytes, want %d", len(got), len(content))
+			}
+			info, _ := os.Stat(tt.dst)
+			if info.Size() != int64(len(content)) {
+				t.Errorf("CopyFile() size = %d, want %d", info.Size(), len(content))
+			}
+			if info.Mode().Perm()&^tt.wantMode != 0 {
+				t.Errorf("CopyFile() mode = %v, want at most %v", info.Mode().Perm(), tt.wantMode)
+			}
+		})
+	}
+
+	if got, _ := os.ReadFile(existingPath); string(got) != "keep" {
+		t.Errorf("CopyFile() overwrote existing destination: %q", got)
+	}
+}
+
+func TestGetMimeTypeFromExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		want string
+	}{
+		{
+			name: "With dot",
+			ext:  ".jpg",
+			want: "image/jpeg",
+		},
+		{
+			name: "Without dot",
+			ext:  "png",
+			want: "image/png",
+		},
+		{
+			name: "Unknown",
+			ext:  ".unknown",
+			want: "application/octet-stream",
+		},
+		{
+			name: "Empty",
+			ext:  "",
+			want: "application/octet-stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filesystem.GetMimeTypeFromExtension(tt.ext); got != tt.want {
+				t.Errorf("GetMimeTypeFromExtension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMimeTypeFromContent(t *testing.T) {
+	tempDir := t.TempDir()
+	textPath := filepath.Join(tempDir, "text.txt")
+	imagePath := filepath.Join(tempDir, "image.png")
+	pdfPath := filepath.Join(tempDir, "document.pdf")
+	emptyPath := filepath.Join(tempDir, "empty.txt")
+	nonexistentPath := filepath.Join(tempDir, "nonexistent.txt")
+	longPath := filepath.Join(tempDir, string(make([]rune, 4097)))
+
+	// Setup test files
+	os.WriteFile(textPath, []byte("Hello, world!"), 0600)
+	// Minimal PNG header
+	os.WriteFile(imagePath, []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, 0600)
+	// Minimal PDF header
+	os.WriteFile(pdfPath, []byte("%PDF-1.4\n"), 0600)
+	os.WriteFile(emptyPath, []byte{}, 0600)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Text file",
+			path:    textPath,
+			want:    "text/plain; charset=utf-8",
+			wantErr: false,
+		},
+		{
+			name:    "Image file",
+			path:    imagePath,
+			want:    "image/png",
+			wantErr: false,
+		},
+		{
+			name:    "PDF file",
+			path:    pdfPath,
+			want:    "application/pdf",
+			wantErr: false,
+		},
+		{
+			name:    "Empty file",
+			path:    emptyPath,
+			want:    "application/octet-stream",
+			wantErr: false,
+		},
+		{
+			name:    "Non-existent file",
+			path:    nonexistentPath,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Directory",
+			path:    tempDir,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Root path",
+			path:    ".",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Path too long",
+			path:    longPath,
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.GetMimeTypeFromContent(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMimeTypeFromContent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetMimeTypeFromContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHead(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "data.txt")
+	os.WriteFile(filePath, []byte("Hello, world!"), 0600)
+	longPath := filepath.Join(tempDir, string(make([]rune, 4097)))
+
+	tests := []struct {
+		name    string
+		path    string
+		n       int
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Fewer bytes than file size",
+			path: filePath,
+			n:    5,
+			want: "Hello",
+		},
+		{
+			name: "Past EOF",
+			path: filePath,
+			n:    100,
+			want: "Hello, world!",
+		},
+		{
+			name: "Huge n on small file",
+			path: filePath,
+			n:    1 << 40,
+			want: "Hello, world!",
+		},
+		{
+			name: "Zero bytes",
+			path: filePath,
+			n:    0,
+			want: "",
+		},
+		{
+			name:    "Negative n",
+			path:    filePath,
+			n:       -1,
+			wantErr: "n must be non-negative",
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			n:       5,
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Path too long",
+			path:    longPath,
+			n:       5,
+			wantErr: "path too long",
+		},
+		{
+			name:    "Non-existent file",
+			path:    filepath.Join(tempDir, "nonexistent.txt"),
+			n:       5,
+			wantErr: "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.ReadHead(tt.path, tt.n)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadHead() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadHead() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReadHead() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileHash(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "data.txt")
+	os.WriteFile(filePath, []byte("hello"), 0600)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+
+	tests := []struct {
+		name    string
+		path    string
+		algo    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "MD5",
+			path: filePath,
+			algo: "md5",
+			want: "5d41402abc4b2a76b9719d911017c592",
+		},
+		{
+			name: "SHA1",
+			path: filePath,
+			algo: "sha1",
+			want: "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		},
+		{
+			name: "SHA256",
+			path: filePath,
+			algo: "sha256",
+			want: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			name: "Default algorithm",
+			path: filePath,
+			algo: "",
+			want: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			name: "Uppercase algorithm",
+			path: filePath,
+			algo: "SHA256",
+			want: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			name:    "Unknown algorithm",
+			path:    filePath,
+			algo:    "crc32",
+			wantErr: "unsupported hash algorithm",
+		},
+		{
+			name:    "Directory",
+			path:    dirPath,
+			algo:    "sha256",
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name:    "Non-existent file",
+			path:    filepath.Join(tempDir, "nonexistent.txt"),
+			algo:    "sha256",
+			wantErr: "no such file",
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			algo:    "sha256",
+			wantErr: "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.FileHash(tt.path, tt.algo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FileHash() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("FileHash() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FileHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "app.js")
+	os.WriteFile(filePath, []byte("console.log(1)"), 0600)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+
+	for i, strong := range []bool{false, true} {
+		first, err := filesystem.ETag(filePath, strong)
+		if err != nil {
+			t.Fatalf("ETag(strong=%v) unexpected error = %v", strong, err)
+		}
+		if strong == strings.HasPrefix(first, "W/") || !strings.HasSuffix(first, `"`) {
+			t.Errorf("ETag(strong=%v) = %s, invalid format", strong, first)
+		}
+		second, _ := filesystem.ETag(filePath, strong)
+		if first != second {
+			t.Errorf("ETag(strong=%v) not stable: %s != %s", strong, first, second)
+		}
+		os.WriteFile(filePath, []byte(fmt.Sprintf("console.log(%d)", i+2)), 0600)
+		mtime := time.Now().Add(time.Duration(i+1) * time.Hour)
+		os.Chtimes(filePath, mtime, mtime)
+		changed, _ := filesystem.ETag(filePath, strong)
+		if changed == first {
+			t.Errorf("ETag(strong=%v) unchanged after modification: %s", strong, changed)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Directory",
+			path:    dirPath,
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name:    "Non-existent file",
+			path:    filepath.Join(tempDir, "nonexistent.js"),
+			wantErr: "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := filesystem.ETag(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ETag() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyLayout(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "cmd", "app"), 0755)
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x"), 0600)
+	os.WriteFile(filepath.Join(root, "cmd", "app", "main.go"), []byte("package main"), 0600)
+	os.Mkdir(filepath.Join(root, "docs"), 0755)
+	filePath := filepath.Join(root, "go.mod")
+
+	tests := []struct {
+		name     string
+		root     string
+		required []string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "All present",
+			root:     root,
+			required: []string{"go.mod", "cmd/", "cmd/app/", "cmd/app/main.go"},
+			want:     nil,
+		},
+		{
+			name:     "Some missing",
+			root:     root,
+			required: []string{"go.mod", "README.md", "internal/", "cmd/"},
+			want:     []string{"README.md", "internal/"},
+		},
+		{
+			name:     "Wrong type",
+			root:     root,
+			required: []string{"docs", "go.mod/", "cmd/app/main.go"},
+			want:     []string{"docs", "go.mod/"},
+		},
+		{
+			name:     "Empty root",
+			root:     "",
+			required: []string{"go.mod"},
+			wantErr:  "path cannot be empty or root",
+		},
+		{
+			name:     "Root is file",
+			root:     filePath,
+			required: []string{"go.mod"},
+			wantErr:  "is a file, not a directory",
+		},
+		{
+			name:     "Root does not exist",
+			root:     filepath.Join(root, "nonexistent"),
+			required: []string{"go.mod"},
+			wantErr:  "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.VerifyLayout(tt.root, tt.required)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("VerifyLayout() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("VerifyLayout() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VerifyLayout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	tempDir := t.TempDir()
+	unixPath := filepath.Join(tempDir, "unix.log")
+	os.WriteFile(unixPath, []byte("first\nsecond\n\nfourth\n"), 0600)
+	windowsPath := filepath.Join(tempDir, "windows.log")
+	os.WriteFile(windowsPath, []byte("first\r\nsecond\r\n\r\nfourth"), 0600)
+	longPath := filepath.Join(tempDir, "long.log")
+	longLine := strings.Repeat("x", 100*1024)
+	os.WriteFile(longPath, []byte("short\n"+longLine+"\n"), 0600)
+	emptyPath := filepath.Join(tempDir, "empty.log")
+	os.WriteFile(emptyPath, []byte{}, 0600)
+	dirPath := filepath.Join(tempDir, "dir")
+	os.Mkdir(dirPath, 0755)
+
+	tests := []struct {
+		name          string
+		path          string
+		maxLineLength []int
+		want          []string
+		wantErr       string
+	}{
+		{
+			name: "Unix line endings",
+			path: unixPath,
+			want: []string{"first", "second", "", "fourth"},
+		},
+		{
+			name: "Windows line endings without trailing newline",
+			path: windowsPath,
+			want: []string{"first", "second", "", "fourth"},
+		},
+		{
+			name: "Line longer than default scanner buffer",
+			path: longPath,
+			want: []string{"short", longLine},
+		},
+		{
+			name:          "Line exceeds configured maximum",
+			path:          longPath,
+			maxLineLength: []int{1024},
+			wantErr:       "token too long",
+		},
+		{
+			name:          "Line exactly at configured maximum",
+			path:          windowsPath,
+			maxLineLength: []int{6},
+			want:          []string{"first", "second", "", "fourth"},
+		},
+		{
+			name:          "Maximum near MaxInt",
+			path:          unixPath,
+			maxLineLength: []int{math.MaxInt},
+			want:          []string{"first", "second", "", "fourth"},
+		},
+		{
+			name:          "Non-positive maximum",
+			path:          unixPath,
+			maxLineLength: []int{0},
+			wantErr:       "max line length must be positive",
+		},
+		{
+			name: "Empty file",
+			path: emptyPath,
+			want: nil,
+		},
+		{
+			name:    "Directory",
+			path:    dirPath,
+			wantErr: "is a directory, not a file",
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.ReadLines(tt.path, tt.maxLineLength...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadLines() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadLines() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTextFile(t *testing.T) {
+	tempDir := t.TempDir()
+	text := "héllo, 世界 🌍"
+	plainPath := filepath.Join(tempDir, "plain.txt")
+	os.WriteFile(plainPath, []byte(text), 0600)
+	utf8BOMPath := filepath.Join(tempDir, "utf8bom.txt")
+	os.WriteFile(utf8BOMPath, append([]byte{0xEF, 0xBB, 0xBF}, text...), 0600)
+	utf16LE := []byte{0xFF, 0xFE}
+	utf16BE := []byte{0xFE, 0xFF}
+	for _, u := range utf16.Encode([]rune(text)) {
+		utf16LE = binary.LittleEndian.AppendUint16(utf16LE, u)
+		utf16BE = binary.BigEndian.AppendUint16(utf16BE, u)
+	}
+	utf16LEPath := filepath.Join(tempDir, "utf16le.txt")
+	os.WriteFile(utf16LEPath, utf16LE, 0600)
+	utf16BEPath := filepath.Join(tempDir, "utf16be.txt")
+	os.WriteFile(utf16BEPath, utf16BE, 0600)
+	oddPath := filepath.Join(tempDir, "odd.txt")
+	os.WriteFile(oddPath, []byte{0xFF, 0xFE, 0x41}, 0600)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Plain UTF-8",
+			path: plainPath,
+			want: text,
+		},
+		{
+			name: "UTF-8 with BOM",
+			path: utf8BOMPath,
+			want: text,
+		},
+		{
+			name: "UTF-16LE with BOM",
+			path: utf16LEPath,
+			want: text,
+		},
+		{
+			name: "UTF-16BE with BOM",
+			path: utf16BEPath,
+			want: text,
+		},
+		{
+			name:    "Odd UTF-16 length",
+			path:    oddPath,
+			wantErr: "odd byte length",
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "Non-existent file",
+			path:    filepath.Join(tempDir, "nonexistent.txt"),
+			wantErr: "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.ReadTextFile(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadTextFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadTextFile() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadTextFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tempDir := t.TempDir()
+	emptyFile := filepath.Join(tempDir, "empty.txt")
+	os.WriteFile(emptyFile, []byte{}, 0600)
+	fullFile := filepath.Join(tempDir, "full.txt")
+	os.WriteFile(fullFile, []byte("content"), 0600)
+	emptyDir := filepath.Join(tempDir, "emptydir")
+	os.Mkdir(emptyDir, 0755)
+	fullDir := filepath.Join(tempDir, "fulldir")
+	os.Mkdir(fullDir, 0755)
+	os.WriteFile(filepath.Join(fullDir, ".hidden"), []byte{}, 0600)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    bool
+		wantErr string
+	}{
+		{name: "Empty file", path: emptyFile, want: true},
+		{name: "Non-empty file", path: fullFile, want: false},
+		{name: "Empty directory", path: emptyDir, want: true},
+		{name: "Directory with hidden file", path: fullDir, want: false},
+		{name: "Nonexistent path", path: filepath.Join(tempDir, "missing"), wantErr: "no such file or directory"},
+		{name: "Empty path", path: "", wantErr: "path cannot be empty or root"},
+		{name: "Path too long", path: strings.Repeat("a", 4097), wantErr: "path too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.IsEmpty(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("IsEmpty() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("IsEmpty() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindStale(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	old := time.Now().Add(-48 * time.Hour)
+	files := map[string]bool{ // path -> stale
+		"fresh.txt":     false,
+		"old.txt":       true,
+		"sub/old.log":   true,
+		"sub/fresh.log": false,
+	}
+	for name, stale := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.WriteFile(path, []byte("data"), 0600)
+		if stale {
+			os.Chtimes(path, old, old)
+		}
+	}
+	// An aged directory must not be reported
+	os.Chtimes(filepath.Join(root, "sub"), old, old)
+	emptyDir := t.TempDir()
+	filePath := filepath.Join(root, "fresh.txt")
+
+	tests := []struct {
+		name      string
+		dir       string
+		olderThan time.Duration
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "Only stale files",
+			dir:       root,
+			olderThan: 24 * time.Hour,
+			want:      []string{filepath.Join(root, "old.txt"), filepath.Join(root, "sub", "old.log")},
+		},
+		{
+			name:      "Nothing old enough",
+			dir:       root,
+			olderThan: 72 * time.Hour,
+			want:      nil,
+		},
+		{
+			name:      "Empty directory",
+			dir:       emptyDir,
+			olderThan: time.Hour,
+			want:      nil,
+		},
+		{
+			name:      "Negative duration",
+			dir:       root,
+			olderThan: -time.Hour,
+			wantErr:   "olderThan must be non-negative",
+		},
+		{
+			name:      "Path is file",
+			dir:       filePath,
+			olderThan: time.Hour,
+			wantErr:   "is a file, not a directory",
+		},
+		{
+			name:      "Empty path",
+			dir:       "",
+			olderThan: time.Hour,
+			wantErr:   "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.FindStale(tt.dir, tt.olderThan)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FindStale() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("FindStale() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "a", "b"), 0755)
+	os.MkdirAll(filepath.Join(root, "empty"), 0755)
+	os.WriteFile(filepath.Join(root, "top.txt"), make([]byte, 100), 0600)
+	os.WriteFile(filepath.Join(root, "a", "mid.bin"), make([]byte, 2048), 0600)
+	os.WriteFile(filepath.Join(root, "a", "b", "deep.dat"), make([]byte, 7), 0600)
+	os.WriteFile(filepath.Join(root, "a", "b", "zero.dat"), nil, 0600)
+	// Symlinks must not be counted or followed
+	os.Symlink(filepath.Join(root, "a", "mid.bin"), filepath.Join(root, "link.bin"))
+	os.Symlink(root, filepath.Join(root, "a", "loop"))
+	emptyDir := t.TempDir()
+	filePath := filepath.Join(root, "top.txt")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    int64
+		wantErr string
+	}{
+		{
+			name: "Nested directories",
+			path: root,
+			want: 100 + 2048 + 7,
+		},
+		{
+			name: "Subdirectory",
+			path: filepath.Join(root, "a", "b"),
+			want: 7,
+		},
+		{
+			name: "Empty directory",
+			path: emptyDir,
+			want: 0,
+		},
+		{
+			name:    "Path is file",
+			path:    filePath,
+			wantErr: "is a file, not a directory",
+		},
+		{
+			name:    "Path does not exist",
+			path:    filepath.Join(root, "missing"),
+			wantErr: "no such file or directory",
+		},
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.DirSize(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("DirSize() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("DirSize() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DirSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "sub", "deeper"), 0755)
+	os.Mkdir(filepath.Join(root, "dir.csv"), 0755)
+	for _, name := range []string{"a.csv", "b.CSV", "notes.txt", "sub/c.csv", "sub/deeper/d.csv", "sub/e.json"} {
+		os.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte("x"), 0600)
+	}
+	os.Symlink(filepath.Join(root, "a.csv"), filepath.Join(root, "link.csv"))
+	filePath := filepath.Join(root, "a.csv")
+
+	tests := []struct {
+		name      string
+		dir       string
+		pattern   string
+		recursive []bool
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:    "Non-recursive extension match",
+			dir:     root,
+			pattern: "*.csv",
+			want:    []string{filepath.Join(root, "a.csv")},
+		},
+		{
+			name:      "Recursive extension match",
+			dir:       root,
+			pattern:   "*.csv",
+			recursive: []bool{true},
+			want: []string{
+				filepath.Join(root, "a.csv"),
+				filepath.Join(root, "sub", "c.csv"),
+				filepath.Join(root, "sub", "deeper", "d.csv"),
+			},
+		},
+		{
+			name:    "Empty pattern matches all files",
+			dir:     root,
+			pattern: "",
+			want:    []string{filepath.Join(root, "a.csv"), filepath.Join(root, "b.CSV"), filepath.Join(root, "notes.txt")},
+		},
+		{
+			name:    "Character class",
+			dir:     root,
+			pattern: "[ab].*",
+			want:    []string{filepath.Join(root, "a.csv"), filepath.Join(root, "b.CSV")},
+		},
+		{
+			name:    "No match",
+			dir:     root,
+			pattern: "*.xml",
+			want:    []string{},
+		},
+		{
+			name:    "Malformed pattern",
+			dir:     root,
+			pattern: "[",
+			wantErr: "invalid pattern",
+		},
+		{
+			name:    "Path is file",
+			dir:     filePath,
+			pattern: "*",
+			wantErr: "is a file, not a directory",
+		},
+		{
+			name:    "Empty path",
+			dir:     "",
+			pattern: "*",
+			wantErr: "path cannot be empty or root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.ListFiles(tt.dir, tt.pattern, tt.recursive...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ListFiles() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ListFiles() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkSafe(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "a", "b"), 0755)
+	os.WriteFile(filepath.Join(root, "a", "b", "file.txt"), []byte("x"), 0600)
+	os.WriteFile(filepath.Join(root, "top.txt"), []byte("x"), 0600)
+	// Cycle back to the root and a second link to an already visited directory
+	os.Symlink(root, filepath.Join(root, "a", "b", "loop"))
+	os.Symlink(filepath.Join(root, "a"), filepath.Join(root, "z-alias"))
+	// A separate directory only reachable through a symlink
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(outside, "ext.txt"), []byte("x"), 0600)
+	os.Symlink(outside, filepath.Join(root, "external"))
+	os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "broken"))
+
+	rel := func(path string) string {
+		r, _ := filepath.Rel(root, path)
+		return filepath.ToSlash(r)
+	}
+
+	t.Run("Terminates on cycle and follows links", func(t *testing.T) {
+		var got []string
+		done := make(chan error, 1)
+		go func() {
+			done <- filesystem.WalkSafe(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				entry := rel(path)
+				if d.IsDir() {
+					entry += "/"
+				}
+				got = append(got, entry)
+				return nil
+			})
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("WalkSafe() unexpected error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("WalkSafe() did not terminate on a symlink cycle")
+		}
+		want := []string{"./", "a/", "a/b/", "a/b/file.txt", "broken", "external/", "external/ext.txt", "top.txt"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("WalkSafe() visited %v, want %v", got, want)
+		}
+	})
+
+	t.Run("SkipDir", func(t *testing.T) {
+		var got []string
+		err := filesystem.WalkSafe(root, func(path string, d fs.DirEntry, err error) error {
+			if d.IsDir() && d.Name() == "a" {
+				return filepath.SkipDir
+			}
+			got = append(got, rel(path))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WalkSafe() unexpected error = %v", err)
+		}
+		for _, p := range got {
+			if strings.HasPrefix(p, "a/") {
+				t.Errorf("WalkSafe() visited %s inside skipped directory", p)
+			}
+		}
+	})
+
+	t.Run("Error from fn stops walk", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := filesystem.WalkSafe(root, func(path string, d fs.DirEntry, err error) error {
+			calls++
+			return errStop
+		})
+		if err != errStop || calls != 1 {
+			t.Errorf("WalkSafe() error = %v after %d calls, want stop after 1", err, calls)
+		}
+	})
+
+	t.Run("Missing root", func(t *testing.T) {
+		err := filesystem.WalkSafe(filepath.Join(root, "nope"), func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if !os.IsNotExist(err) {
+			t.Errorf("WalkSafe() error = %v, want not exist", err)
+		}
+	})
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		bytes   int64
+		decimal []bool
+		want    string
+	}{
+		{"Zero", 0, nil, "0 B"},
+		{"Bytes", 512, nil, "512 B"},
+		{"Just below KiB", 1023, nil, "1023 B"},
+		{"One KiB", 1024, nil, "1 KiB"},
+		{"Fractional MiB", 11010048, nil, "10.5 MiB"},
+		{"GiB", 5 << 30, nil, "5 GiB"},
+		{"Rounds up to next unit", 1024*1024 - 1, nil, "1 MiB"},
+		{"Max int64", math.MaxInt64, nil, "8 EiB"},
+		{"Negative", -1536, nil, "-1.5 KiB"},
+		{"Decimal bytes", 999, []bool{true}, "999 B"},
+		{"Decimal MB", 11010048, []bool{true}, "11 MB"},
+		{"Decimal fractional GB", 1_500_000_000, []bool{true}, "1.5 GB"},
+		{"Explicit binary", 2048, []bool{false}, "2 KiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filesystem.HumanSize(tt.bytes, tt.decimal...); got != tt.want {
+				t.Errorf("HumanSize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int64
+		wantErr string
+	}{
+		{"Decimal MB", "10MB", 10_000_000, ""},
+		{"Binary fractional GiB", "1.5GiB", 1610612736, ""},
+		{"Plain bytes", "512", 512, ""},
+		{"Bytes unit with space", "512 B", 512, ""},
+		{"Case insensitive", "2 kib", 2048, ""},
+		{"Leading fraction", ".5KB", 500, ""},
+		{"Surrounding whitespace", "  3 MiB ", 3 << 20, ""},
+		{"Round trip", filesystem.HumanSize(11010048), 11010048, ""},
+		{"Empty", "", 0, "size cannot be empty"},
+		{"No number", "MB", 0, "invalid size"},
+		{"Negative", "-5MB", 0, "invalid size"},
+		{"Malformed number", "1.2.3MB", 0, "invalid size"},
+		{"Unknown unit", "10 XB", 0, "invalid size unit"},
+		{"Overflow", "9EiB", 0, "overflows int64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filesystem.ParseSize(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseSize() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseSize() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "Empty filename",
+			filename: "",
+			wantErr:  "filename cannot be empty",
+		},
+		{
+			name:     "Invalid chars",
+			filename: "file/name:with?invalid*chars",
 			want:     "file_name_with_invalid_chars",
 		},
 		{
@@ -394,6 +1840,11 @@ func TestSanitizeFilename(t *testing.T) {
 			filename: "file.txt  ",
 			want:     "file.txt",
 		},
+		{
+			name:     "Extension case preserved",
+			filename: "Report.PDF",
+			want:     "Report.PDF",
+		},
 		{
 			name:     "Becomes empty after clean",
 			filename: " . ",
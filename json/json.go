@@ -6,17 +6,27 @@
 package json
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/devify-me/devify-utils/fileio"
 )
 
 // Marshal serializes the given data to JSON format as a byte slice.
 //
-// The function checks that the input data is not nil and that the marshaled output is not empty
-// (i.e., not "{}" or "[]"). If serialization fails or the output is empty, an error is returned.
+// The function checks that the input data is not nil and that the marshaled output is not empty, meaning the literal
+// null (e.g., from a nil pointer, map or slice) or an empty string. Empty objects and arrays such as "{}" and "[]"
+// are valid documents and are returned as-is. If serialization fails or the output is empty, an error is returned.
 //
 // Example:
 //
@@ -41,12 +51,125 @@ func Marshal(data any) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(output) <= 2 { // Check for "{}" or similar minimal output
+	if isEmptyJSON(output) {
 		return nil, errors.New("marshaled JSON is empty")
 	}
 	return output, nil
 }
 
+// MarshalIndent serializes the given data to indented, human-readable JSON format as a byte slice.
+//
+// It behaves like Marshal, but places each object member and array element on its own line, indented by one
+// copy of indent per nesting level (e.g., "  " or "\t"). The same nil and empty-output checks apply.
+//
+// Example:
+//
+//	data := map[string]string{"key": "value"}
+//	output, err := MarshalIndent(data, "  ")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(output)) // Prints the object with "key" on its own line, indented by two spaces
+//
+// Parameters:
+//   - data: The data to serialize to JSON (can be any type supported by encoding/json).
+//   - indent: The string used for each level of indentation.
+//
+// Returns:
+//   - []byte: The indented JSON-encoded data as a byte slice.
+//   - error: An error if the data is nil, cannot be marshaled, or results in empty JSON.
+func MarshalIndent(data any, indent string) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data cannot be nil")
+	}
+	output, err := json.MarshalIndent(data, "", indent)
+	if err != nil {
+		return nil, err
+	}
+	if isEmptyJSON(output) {
+		return nil, errors.New("marshaled JSON is empty")
+	}
+	return output, nil
+}
+
+// MarshalNoEscape serializes the given data to JSON format as a byte slice without escaping HTML characters.
+//
+// By default, encoding/json escapes '<', '>', and '&' as \u003c, \u003e, and \u0026 so that JSON can be embedded
+// safely in HTML. This function disables that escaping, keeping URLs and HTML snippets in string values intact.
+// Do not embed the output in an HTML <script> element without escaping it. Otherwise it behaves like Marshal: the
+// same nil and empty-output checks apply, and no trailing newline is included.
+//
+// Example:
+//
+//	data := map[string]string{"url": "https://example.com/?a=1&b=2"}
+//	output, err := MarshalNoEscape(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(output)) // Prints `{"url":"https://example.com/?a=1&b=2"}`
+//
+// Parameters:
+//   - data: The data to serialize to JSON (can be any type supported by encoding/json).
+//
+// Returns:
+//   - []byte: The JSON-encoded data as a byte slice.
+//   - error: An error if the data is nil, cannot be marshaled, or results in empty JSON.
+func MarshalNoEscape(data any) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data cannot be nil")
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	output := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if isEmptyJSON(output) {
+		return nil, errors.New("marshaled JSON is empty")
+	}
+	return output, nil
+}
+
+// isEmptyJSON reports whether marshaled JSON carries no value: the literal null or an empty string.
+func isEmptyJSON(output []byte) bool {
+	output = bytes.TrimSpace(output)
+	return len(output) == 0 || string(output) == "null" || string(output) == `""`
+}
+
+// EncodeTo serializes the given data to JSON and writes it directly to w, as the streaming counterpart to Marshal.
+//
+// The data is encoded with json.NewEncoder(w).Encode, so the output is written to w (e.g., an http.ResponseWriter
+// or a gzip.Writer) instead of being returned to the caller, and it is followed by a newline. Note that encoding/json
+// still builds the encoding of a single value in memory before writing it; calling EncodeTo once per element writes
+// newline-delimited JSON (JSON Lines) with memory use bounded by the largest element. Unlike Marshal, null and empty
+// strings are written as-is, since they are meaningful responses.
+//
+// Example:
+//
+//	func list(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "application/json")
+//	    if err := EncodeTo(w, users); err != nil {
+//	        log.Println(err)
+//	    }
+//	}
+//
+// Parameters:
+//   - w: The writer to write the JSON data to.
+//   - data: The data to serialize to JSON (can be any type supported by encoding/json).
+//
+// Returns:
+//   - error: An error if w or data is nil, the data cannot be marshaled, or writing fails.
+func EncodeTo(w io.Writer, data any) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	if data == nil {
+		return errors.New("data cannot be nil")
+	}
+	return json.NewEncoder(w).Encode(data)
+}
+
 // Unmarshal parses JSON data into the provided destination.
 //
 // The destination must be a non-nil pointer to a struct, map, or other type supported by encoding/json.
@@ -79,9 +202,79 @@ func Unmarshal(data []byte, dest any) error {
 	return json.Unmarshal(data, dest)
 }
 
+// Valid reports whether data is a single, syntactically valid JSON value.
+//
+// It is a quick check before Unmarshal; use ValidateBytes to find out where invalid data goes wrong.
+//
+// Example:
+//
+//	if !Valid(data) {
+//	    log.Fatal("config is not valid JSON")
+//	}
+//
+// Parameters:
+//   - data: The JSON-encoded data as a byte slice.
+//
+// Returns:
+//   - bool: True if data is valid JSON, false if it is empty or malformed.
+func Valid(data []byte) bool {
+	return json.Valid(data)
+}
+
+// ValidateBytes checks that data is a single, syntactically valid JSON value and describes where it is not.
+//
+// On a syntax error, the returned error includes the line, column, and 0-based byte offset of the offending byte,
+// along with a short snippet of the surrounding input, so that the mistake can be located in a large file.
+// The underlying *json.SyntaxError is wrapped and can be retrieved with errors.As.
+//
+// Example:
+//
+//	err := ValidateBytes([]byte("{\n  \"port\": 8080,\n}"))
+//	fmt.Println(err)
+//	// Prints invalid JSON at line 3, column 1 (offset 18) near "t\": 8080,\n}": invalid character '}' looking for beginning of object key string
+//
+// Parameters:
+//   - data: The JSON-encoded data as a byte slice.
+//
+// Returns:
+//   - error: An error if the data is empty or is not valid JSON, nil otherwise.
+func ValidateBytes(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("JSON data cannot be empty")
+	}
+	if json.Valid(data) {
+		return nil
+	}
+	var raw json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	// SyntaxError.Offset counts the bytes read, including the offending one.
+	offset := int(min(max(syntaxErr.Offset-1, 0), int64(len(data)-1)))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return fmt.Errorf("invalid JSON at line %d, column %d (offset %d) near %q: %w",
+		line, column, offset, errorSnippet(data, offset), syntaxErr)
+}
+
+// errorSnippet returns up to ten bytes of context on each side of offset, adjusted to UTF-8 boundaries.
+func errorSnippet(data []byte, offset int) string {
+	const context = 10
+	start, end := max(offset-context, 0), min(offset+context, len(data))
+	for start > 0 && !utf8.RuneStart(data[start]) {
+		start--
+	}
+	for end < len(data) && !utf8.RuneStart(data[end]) {
+		end++
+	}
+	return string(data[start:end])
+}
+
 // ReadFile reads a JSON file from the specified path and unmarshals it into the provided destination.
 //
-// The function validates that the file path has a ".json" extension and exists using fileio.ValidatePath.
+// The function validates that the file path has a ".json" extension and exists using fileio.ValidateReadPath.
 // It also checks that the file is not empty before attempting to unmarshal the data into the destination,
 // which must be a non-nil pointer to a struct, map, or other type supported by encoding/json.
 //
@@ -114,9 +307,70 @@ func ReadFile(path string, dest any) error {
 	return Unmarshal(data, dest)
 }
 
+// ReadLinesTyped reads a JSON Lines (ndjson) file and decodes each line into a value of type T.
+//
+// The function validates that the file path has a ".jsonl" or ".ndjson" extension using fileio.ValidateReadPath,
+// then decodes the file line by line, so only one line is held in memory at a time in addition to the result.
+// Blank lines are skipped. If a line cannot be decoded, the returned error names its 1-based line number.
+// An empty file yields an empty slice.
+//
+// Example:
+//
+//	type entry struct {
+//	    Level string `json:"level"`
+//	    Msg   string `json:"msg"`
+//	}
+//	entries, err := ReadLinesTyped[entry]("app.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(len(entries)) // Prints the number of log entries
+//
+// Parameters:
+//   - path: The file path of the JSON Lines file to read.
+//
+// Returns:
+//   - []T: The decoded values, in file order.
+//   - error: An error if the path is invalid, the file cannot be read, or a line cannot be decoded.
+func ReadLinesTyped[T any](path string) ([]T, error) {
+	ext := ".jsonl"
+	if filepath.Ext(path) == ".ndjson" {
+		ext = ".ndjson"
+	}
+	if err := fileio.ValidateReadPath(path, ext); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := []T{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		result = append(result, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+	}
+	return result, nil
+}
+
 // WriteFile serializes the given data to JSON and writes it to a file at the specified path.
 //
-// The function validates that the file path has a ".json" extension using fileio.ValidatePath and ensures
+// The function validates that the file path has a ".json" extension using fileio.ValidateWritePath and ensures
 // the parent directories exist using fileio.EnsureDir. The data is marshaled to JSON, and the resulting
 // bytes are written to the file with the specified permissions (defaulting to 0600 if not provided).
 // If the data cannot be marshaled or the file cannot be written, an error is returned.
@@ -145,6 +399,44 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	if err != nil {
 		return err
 	}
+	return writeFile(path, output, perm)
+}
+
+// WriteFileIndent serializes the given data to indented JSON and writes it to a file at the specified path.
+//
+// It behaves like WriteFile, but formats the output with MarshalIndent so that files meant to be edited by hand
+// stay readable. The file ends with a trailing newline.
+//
+// Example:
+//
+//	cfg := map[string]any{"port": 8080, "debug": true}
+//	err := WriteFileIndent(cfg, "config.json", "  ", 0o644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - data: The data to serialize and write to the file (can be any type supported by encoding/json).
+//   - path: The file path where the JSON data will be written.
+//   - indent: The string used for each level of indentation (e.g., "  " or "\t").
+//   - perm: Optional file permission mode (os.FileMode). Defaults to 0600 if not provided.
+//
+// Returns:
+//   - error: An error if the path is invalid, data cannot be marshaled, directories cannot be created,
+//     or the file cannot be written.
+func WriteFileIndent(data any, path string, indent string, perm ...os.FileMode) error {
+	if err := fileio.ValidateWritePath(path, ".json"); err != nil {
+		return err
+	}
+	output, err := MarshalIndent(data, indent)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(output, '\n'), perm)
+}
+
+// writeFile writes output to path, creating parent directories and using perm (default 0600).
+func writeFile(path string, output []byte, perm []os.FileMode) error {
 	if err := fileio.EnsureDir(path, 0o755); err != nil {
 		return err
 	}
@@ -154,3 +446,245 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	}
 	return os.WriteFile(path, output, fileMode)
 }
+
+// querySegment is a single step of a parsed Query path: an object key, an array index, or a wildcard.
+type querySegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Query extracts all values in JSON data that match a minimal JSONPath-like expression.
+//
+// The path must start with "$" (the document root) followed by any combination of dot notation (".name"),
+// array indexes ("[0]"), and wildcards ("[*]", matching every array element or object member value, with object
+// members visited in key order). Other JSONPath features, such as recursive descent (".."), slices, filters,
+// and quoted keys, are not supported and result in an error. Values are decoded as by encoding/json into any
+// (e.g., numbers as float64). A path that matches nothing returns an empty slice and no error.
+//
+// Example:
+//
+//	data := []byte(`{"items":[{"id":"a"},{"id":"b"}]}`)
+//	ids, err := Query(data, "$.items[*].id")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ids) // Prints [a b]
+//
+// Parameters:
+//   - data: The JSON-encoded data as a byte slice.
+//   - path: The JSONPath-like expression selecting the values to extract.
+//
+// Returns:
+//   - []any: All matching values, in document order.
+//   - error: An error if the data is empty or invalid, or the path is malformed or unsupported.
+func Query(data []byte, path string) ([]any, error) {
+	segments, err := parseQuery(path)
+	if err != nil {
+		return nil, err
+	}
+	var root any
+	if err := Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	current := []any{root}
+	for _, seg := range segments {
+		next := []any{}
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]any:
+				if seg.wildcard {
+					keys := make([]string, 0, len(v))
+					for k := range v {
+						keys = append(keys, k)
+					}
+					slices.Sort(keys)
+					for _, k := range keys {
+						next = append(next, v[k])
+					}
+				} else if !seg.isIndex {
+					if member, ok := v[seg.key]; ok {
+						next = append(next, member)
+					}
+				}
+			case []any:
+				if seg.wildcard {
+					next = append(next, v...)
+				} else if seg.isIndex && seg.index < len(v) {
+					next = append(next, v[seg.index])
+				}
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// parseQuery splits a Query path into segments, rejecting unsupported syntax.
+func parseQuery(path string) ([]querySegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("query path must start with \"$\": %q", path)
+	}
+	var segments []querySegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" || key == "*" {
+				return nil, fmt.Errorf("unsupported query path %q: expected a key after \".\"", path)
+			}
+			segments = append(segments, querySegment{key: key})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query path %q: unclosed \"[\"", path)
+			}
+			inner := rest[1:end]
+			if inner == "*" {
+				segments = append(segments, querySegment{wildcard: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("unsupported query path %q: %q is not a wildcard or non-negative index", path, inner)
+				}
+				segments = append(segments, querySegment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid query path %q: unexpected character %q", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// Merge deep-merges two JSON objects, with values from override taking precedence over base.
+//
+// Both documents must be JSON objects at the top level, so that an override file that is accidentally an array, a
+// scalar, or null is rejected rather than replacing the whole base document. Objects are merged recursively: keys
+// present only in base are kept, keys present only in override are added, and keys present in both are merged if both
+// values are objects, or replaced by the override value otherwise. Arrays and scalars (including null) are replaced
+// wholesale, never concatenated. Numbers are preserved exactly as written, and the merged object keys are emitted in
+// sorted order, as by encoding/json.
+//
+// Example:
+//
+//	base := []byte(`{"server":{"host":"localhost","port":8080},"tags":["a","b"]}`)
+//	override := []byte(`{"server":{"port":9090},"tags":["c"]}`)
+//	merged, err := Merge(base, override)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(merged)) // Prints {"server":{"host":"localhost","port":9090},"tags":["c"]}
+//
+// Parameters:
+//   - base: The JSON document providing default values.
+//   - override: The JSON document whose values win on conflicts.
+//
+// Returns:
+//   - []byte: The merged JSON document.
+//   - error: An error if either input is empty or invalid JSON, or if either top-level value is not an object.
+func Merge(base, override []byte) ([]byte, error) {
+	baseValue, err := decodeMergeInput(base, "base")
+	if err != nil {
+		return nil, err
+	}
+	overrideValue, err := decodeMergeInput(override, "override")
+	if err != nil {
+		return nil, err
+	}
+	baseKind, overrideKind := jsonKind(baseValue), jsonKind(overrideValue)
+	if baseKind != "object" || overrideKind != "object" {
+		return nil, fmt.Errorf("cannot merge top-level %s with %s: both must be objects", baseKind, overrideKind)
+	}
+	return json.Marshal(mergeValues(baseValue, overrideValue))
+}
+
+// decodeMergeInput decodes a single JSON value, keeping numbers as json.Number and rejecting trailing data.
+func decodeMergeInput(data []byte, name string) (any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("%s JSON cannot be empty", name)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid %s JSON: %w", name, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid %s JSON: unexpected data after top-level value", name)
+	}
+	return v, nil
+}
+
+// mergeValues recursively merges override into base, returning the merged value.
+func mergeValues(base, override any) any {
+	baseMap, baseOK := base.(map[string]any)
+	overrideMap, overrideOK := override.(map[string]any)
+	if !baseOK || !overrideOK {
+		return override
+	}
+	for k, v := range overrideMap {
+		if existing, ok := baseMap[k]; ok {
+			baseMap[k] = mergeValues(existing, v)
+		} else {
+			baseMap[k] = v
+		}
+	}
+	return baseMap
+}
+
+// jsonKind returns a human-readable name for the JSON type of a decoded value.
+func jsonKind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// Serializer implements fileio.Serializer for JSON using the functions of this package.
+//
+// It is registered with fileio.Register for the ".json" extension when this package is imported, so that
+// fileio.ReadAuto can read JSON files.
+type Serializer struct{}
+
+// Marshal serializes the given data to JSON using Marshal.
+func (Serializer) Marshal(data any) ([]byte, error) {
+	return Marshal(data)
+}
+
+// Unmarshal parses JSON data into the provided destination using Unmarshal.
+func (Serializer) Unmarshal(data []byte, dest any) error {
+	return Unmarshal(data, dest)
+}
+
+// ReadFile reads a JSON file into the provided destination using ReadFile.
+func (Serializer) ReadFile(path string, dest any) error {
+	return ReadFile(path, dest)
+}
+
+// WriteFile writes the given data to a JSON file using WriteFile.
+func (Serializer) WriteFile(data any, path string, perm ...os.FileMode) error {
+	return WriteFile(data, path, perm...)
+}
+
+func init() {
+	fileio.Register(".json", Serializer{})
+}
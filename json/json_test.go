@@ -1,6 +1,10 @@
 package json_test
 
 import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -38,8 +42,28 @@ func TestMarshal(t *testing.T) {
 			wantErr: "json: unsupported type: chan int",
 		},
 		{
-			name:    "Empty output",
-			data:    struct{}{},
+			name: "Empty struct",
+			data: struct{}{},
+			want: []byte(`{}`),
+		},
+		{
+			name: "Empty slice",
+			data: []int{},
+			want: []byte(`[]`),
+		},
+		{
+			name: "Short scalar",
+			data: 7,
+			want: []byte(`7`),
+		},
+		{
+			name:    "Nil map output",
+			data:    map[string]int(nil),
+			wantErr: "marshaled JSON is empty",
+		},
+		{
+			name:    "Empty string output",
+			data:    "",
 			wantErr: "marshaled JSON is empty",
 		},
 	}
@@ -63,6 +87,194 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalIndent(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		indent  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "Two spaces",
+			data:   testStruct{Name: "Alice", Age: 30},
+			indent: "  ",
+			want:   "{\n  \"name\": \"Alice\",\n  \"age\": 30\n}",
+		},
+		{
+			name:   "Tab nested",
+			data:   map[string]any{"a": []int{1}},
+			indent: "\t",
+			want:   "{\n\t\"a\": [\n\t\t1\n\t]\n}",
+		},
+		{
+			name:    "Nil data",
+			data:    nil,
+			indent:  "  ",
+			wantErr: "data cannot be nil",
+		},
+		{
+			name:   "Empty struct",
+			data:   struct{}{},
+			indent: "  ",
+			want:   "{}",
+		},
+		{
+			name:    "Nil pointer output",
+			data:    (*testStruct)(nil),
+			indent:  "  ",
+			wantErr: "marshaled JSON is empty",
+		},
+		{
+			name:    "Unmarshalable data",
+			data:    make(chan int),
+			indent:  "  ",
+			wantErr: "json: unsupported type: chan int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(tt.data, tt.indent)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MarshalIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("MarshalIndent() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalIndent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalNoEscape(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Ampersand in URL",
+			data: map[string]string{"og:url": "https://example.com/?a=1&b=2"},
+			want: `{"og:url":"https://example.com/?a=1&b=2"}`,
+		},
+		{
+			name: "HTML snippet",
+			data: []string{"<b>bold</b>"},
+			want: `["<b>bold</b>"]`,
+		},
+		{
+			name: "Matches Marshal without HTML",
+			data: testStruct{Name: "Alice", Age: 30},
+			want: `{"name":"Alice","age":30}`,
+		},
+		{
+			name:    "Nil data",
+			data:    nil,
+			wantErr: "data cannot be nil",
+		},
+		{
+			name: "Empty map",
+			data: map[string]int{},
+			want: `{}`,
+		},
+		{
+			name:    "Nil slice output",
+			data:    []string(nil),
+			wantErr: "marshaled JSON is empty",
+		},
+		{
+			name:    "Unmarshalable data",
+			data:    make(chan int),
+			wantErr: "json: unsupported type: chan int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalNoEscape(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MarshalNoEscape() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("MarshalNoEscape() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalNoEscape() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestEncodeTo(t *testing.T) {
+	t.Run("Slice to buffer", func(t *testing.T) {
+		var buf bytes.Buffer
+		users := []testStruct{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 25}}
+		if err := json.EncodeTo(&buf, users); err != nil {
+			t.Fatalf("EncodeTo() unexpected error = %v", err)
+		}
+		if want := `[{"name":"Alice","age":30},{"name":"Bob","age":25}]` + "\n"; buf.String() != want {
+			t.Errorf("EncodeTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("Per element writes JSON Lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.jsonl")
+		file, _ := os.Create(path)
+		for i := 0; i < 3; i++ {
+			if err := json.EncodeTo(file, testStruct{Name: "user", Age: i}); err != nil {
+				t.Fatalf("EncodeTo() unexpected error = %v", err)
+			}
+		}
+		file.Close()
+		got, err := json.ReadLinesTyped[testStruct](path)
+		if err != nil || len(got) != 3 || got[2].Age != 2 {
+			t.Errorf("ReadLinesTyped() after EncodeTo() = %v, %v", got, err)
+		}
+	})
+
+	t.Run("Empty slice is written", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := json.EncodeTo(&buf, []int{}); err != nil || buf.String() != "[]\n" {
+			t.Errorf("EncodeTo() = %q, %v, want %q", buf.String(), err, "[]\n")
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		w       io.Writer
+		data    any
+		wantErr string
+	}{
+		{"Nil writer", nil, testStruct{}, "writer cannot be nil"},
+		{"Nil data", &bytes.Buffer{}, nil, "data cannot be nil"},
+		{"Unmarshalable data", &bytes.Buffer{}, make(chan int), "json: unsupported type: chan int"},
+		{"Writer error", failingWriter{}, testStruct{}, "connection reset"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.EncodeTo(tt.w, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("EncodeTo() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -122,6 +334,63 @@ func TestUnmarshal(t *testing.T) {
 	}
 }
 
+func TestValidateBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "Valid object", data: `{"name":"Alice","tags":["a","b"]}`},
+		{name: "Valid scalar", data: `42`},
+		{name: "Empty data", data: "", wantErr: "JSON data cannot be empty"},
+		{
+			name:    "Trailing comma on later line",
+			data:    "{\n  \"port\": 8080,\n}",
+			wantErr: `invalid JSON at line 3, column 1 (offset 18) near "t\": 8080,\n}": invalid character '}'`,
+		},
+		{
+			name:    "Unexpected end",
+			data:    `{"a":1`,
+			wantErr: "unexpected end of JSON input",
+		},
+		{
+			name:    "Trailing data",
+			data:    `{"a":1} x`,
+			wantErr: "line 1, column 9 (offset 8)",
+		},
+		{
+			name:    "Snippet keeps multi-byte characters intact",
+			data:    `{"ééééé":"ü", x}`,
+			wantErr: `near "é\":\"ü\", x}"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.ValidateBytes([]byte(tt.data))
+			if got := json.Valid([]byte(tt.data)); got != (tt.wantErr == "") {
+				t.Errorf("Valid() = %v, want %v", got, tt.wantErr == "")
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateBytes() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateBytes() unexpected error = %v", err)
+			}
+		})
+	}
+
+	t.Run("Wraps SyntaxError", func(t *testing.T) {
+		var syntaxErr *stdjson.SyntaxError
+		if err := json.ValidateBytes([]byte(`[1,]`)); !errors.As(err, &syntaxErr) {
+			t.Errorf("ValidateBytes() error = %v, want a wrapped *json.SyntaxError", err)
+		}
+	})
+}
+
 func TestReadFile(t *testing.T) {
 	tempDir := t.TempDir()
 	validPath := filepath.Join(tempDir, "test.json")
@@ -221,6 +490,83 @@ func TestReadFile(t *testing.T) {
 	}
 }
 
+func TestReadLinesTyped(t *testing.T) {
+	tempDir := t.TempDir()
+	validPath := filepath.Join(tempDir, "valid.jsonl")
+	ndjsonPath := filepath.Join(tempDir, "valid.ndjson")
+	malformedPath := filepath.Join(tempDir, "malformed.jsonl")
+	emptyPath := filepath.Join(tempDir, "empty.jsonl")
+	invalidExtPath := filepath.Join(tempDir, "test.json")
+
+	os.WriteFile(validPath, []byte("{\"name\":\"Alice\",\"age\":30}\n\n{\"name\":\"Bob\",\"age\":25}\n"), 0600)
+	os.WriteFile(ndjsonPath, []byte(`{"name":"Carol","age":41}`), 0600)
+	os.WriteFile(malformedPath, []byte("{\"name\":\"Alice\",\"age\":30}\n{\"name\":\"Bob\",\n{\"name\":\"Carol\",\"age\":41}\n"), 0600)
+	os.WriteFile(emptyPath, []byte{}, 0600)
+	os.WriteFile(invalidExtPath, []byte(`{"name":"Alice","age":30}`), 0600)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []testStruct
+		wantErr string
+	}{
+		{
+			name:    "Empty path",
+			path:    "",
+			wantErr: "path cannot be empty or root",
+		},
+		{
+			name:    "File not exist",
+			path:    filepath.Join(tempDir, "nonexistent.jsonl"),
+			wantErr: "file does not exist",
+		},
+		{
+			name:    "Invalid extension",
+			path:    invalidExtPath,
+			wantErr: "file must have .jsonl extension",
+		},
+		{
+			name: "Valid file with blank line",
+			path: validPath,
+			want: []testStruct{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 25}},
+		},
+		{
+			name: "Valid ndjson extension",
+			path: ndjsonPath,
+			want: []testStruct{{Name: "Carol", Age: 41}},
+		},
+		{
+			name: "Empty file",
+			path: emptyPath,
+			want: []testStruct{},
+		},
+		{
+			name:    "Malformed middle line",
+			path:    malformedPath,
+			wantErr: "line 2:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.ReadLinesTyped[testStruct](tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadLinesTyped() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadLinesTyped() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadLinesTyped() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	tempDir := t.TempDir()
 	validPath := filepath.Join(tempDir, "test.json")
@@ -316,3 +662,271 @@ func TestWriteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFileIndent(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "config", "app.json")
+
+	if err := json.WriteFileIndent(testStruct{Name: "Alice", Age: 30}, path, "  ", 0644); err != nil {
+		t.Fatalf("WriteFileIndent() unexpected error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "{\n  \"name\": \"Alice\",\n  \"age\": 30\n}\n"; string(got) != want {
+		t.Errorf("WriteFileIndent() wrote %q, want %q", got, want)
+	}
+	var back testStruct
+	if err := json.ReadFile(path, &back); err != nil || back != (testStruct{Name: "Alice", Age: 30}) {
+		t.Errorf("ReadFile() after WriteFileIndent() = %+v, %v", back, err)
+	}
+
+	errTests := []struct {
+		name    string
+		data    any
+		path    string
+		wantErr string
+	}{
+		{"Invalid extension", testStruct{Name: "A"}, filepath.Join(tempDir, "a.txt"), "file must have .json extension"},
+		{"Nil data", nil, filepath.Join(tempDir, "nil.json"), "data cannot be nil"},
+		{"Empty output", (*testStruct)(nil), filepath.Join(tempDir, "empty.json"), "marshaled JSON is empty"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.WriteFileIndent(tt.data, tt.path, "  ")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WriteFileIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "Deep merge objects",
+			base:     `{"server":{"host":"localhost","port":8080,"tls":{"enabled":false,"cert":"a.pem"}},"debug":true}`,
+			override: `{"server":{"port":9090,"tls":{"enabled":true}},"name":"prod"}`,
+			want:     `{"debug":true,"name":"prod","server":{"host":"localhost","port":9090,"tls":{"cert":"a.pem","enabled":true}}}`,
+		},
+		{
+			name:     "Arrays replaced wholesale",
+			base:     `{"tags":["a","b","c"],"nested":{"list":[1,2]}}`,
+			override: `{"tags":["z"],"nested":{"list":[]}}`,
+			want:     `{"nested":{"list":[]},"tags":["z"]}`,
+		},
+		{
+			name:     "Scalar replaces object",
+			base:     `{"db":{"host":"x"}}`,
+			override: `{"db":"sqlite://file.db"}`,
+			want:     `{"db":"sqlite://file.db"}`,
+		},
+		{
+			name:     "Object replaces scalar",
+			base:     `{"db":null}`,
+			override: `{"db":{"host":"x"}}`,
+			want:     `{"db":{"host":"x"}}`,
+		},
+		{
+			name:     "Null override wins",
+			base:     `{"a":1}`,
+			override: `{"a":null}`,
+			want:     `{"a":null}`,
+		},
+		{
+			name:     "Numbers preserved exactly",
+			base:     `{"id":12345678901234567890,"ratio":0.10}`,
+			override: `{}`,
+			want:     `{"id":12345678901234567890,"ratio":0.10}`,
+		},
+		{
+			name:     "Top-level arrays rejected",
+			base:     `[1,2,3]`,
+			override: `[4]`,
+			wantErr:  "cannot merge top-level array with array",
+		},
+		{
+			name:     "Top-level type mismatch",
+			base:     `{"a":1}`,
+			override: `[1]`,
+			wantErr:  "cannot merge top-level object with array",
+		},
+		{
+			name:     "Scalar override rejected",
+			base:     `{"a":1}`,
+			override: `5`,
+			wantErr:  "cannot merge top-level object with number",
+		},
+		{
+			name:     "Null override rejected",
+			base:     `{"a":1}`,
+			override: `null`,
+			wantErr:  "cannot merge top-level object with null",
+		},
+		{
+			name:     "Scalar base rejected",
+			base:     `"x"`,
+			override: `{"a":1}`,
+			wantErr:  "cannot merge top-level string with object",
+		},
+		{
+			name:     "Invalid base",
+			base:     `{"a":`,
+			override: `{}`,
+			wantErr:  "invalid base JSON",
+		},
+		{
+			name:     "Invalid override",
+			base:     `{}`,
+			override: `{"a":1} trailing`,
+			wantErr:  "invalid override JSON",
+		},
+		{
+			name:     "Empty override",
+			base:     `{}`,
+			override: "  ",
+			wantErr:  "override JSON cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Merge([]byte(tt.base), []byte(tt.override))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Merge() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Merge() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	data := []byte(`{
+		"service": {"name": "api", "port": 8080},
+		"items": [{"id": "a", "tags": ["x", "y"]}, {"id": "b"}, {"name": "no id"}],
+		"matrix": [[1, 2], [3, 4]]
+	}`)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		path    string
+		want    []any
+		wantErr string
+	}{
+		{
+			name: "Root",
+			data: []byte(`{"a":1}`),
+			path: "$",
+			want: []any{map[string]any{"a": float64(1)}},
+		},
+		{
+			name: "Nested scalar",
+			data: data,
+			path: "$.service.name",
+			want: []any{"api"},
+		},
+		{
+			name: "Array index",
+			data: data,
+			path: "$.items[1].id",
+			want: []any{"b"},
+		},
+		{
+			name: "Wildcard array projection",
+			data: data,
+			path: "$.items[*].id",
+			want: []any{"a", "b"},
+		},
+		{
+			name: "Nested wildcards",
+			data: data,
+			path: "$.matrix[*][*]",
+			want: []any{float64(1), float64(2), float64(3), float64(4)},
+		},
+		{
+			name: "Object wildcard",
+			data: data,
+			path: "$.service[*]",
+			want: []any{"api", float64(8080)},
+		},
+		{
+			name: "Non-matching path",
+			data: data,
+			path: "$.missing.key",
+			want: []any{},
+		},
+		{
+			name: "Index out of range",
+			data: data,
+			path: "$.items[10]",
+			want: []any{},
+		},
+		{
+			name:    "Missing root",
+			data:    data,
+			path:    "items[*]",
+			wantErr: "must start with",
+		},
+		{
+			name:    "Recursive descent",
+			data:    data,
+			path:    "$..id",
+			wantErr: "unsupported query path",
+		},
+		{
+			name:    "Filter expression",
+			data:    data,
+			path:    "$.items[?(@.id)]",
+			wantErr: "unsupported query path",
+		},
+		{
+			name:    "Unclosed bracket",
+			data:    data,
+			path:    "$.items[0",
+			wantErr: "unclosed",
+		},
+		{
+			name:    "Empty data",
+			data:    []byte{},
+			path:    "$",
+			wantErr: "JSON data cannot be empty",
+		},
+		{
+			name:    "Invalid JSON",
+			data:    []byte(`{invalid}`),
+			path:    "$",
+			wantErr: "invalid character",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Query(tt.data, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Query() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Query() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
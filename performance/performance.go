@@ -1,9 +1,12 @@
 // Package performance provides utilities for measuring performance of functions in devify-utils.
-// It includes functions to measure execution time and is designed to support benchmarking and profiling.
+// It includes functions to measure execution time, latency distributions and memory allocations, and is designed
+// to support benchmarking and profiling.
 package performance
 
 import (
 	"fmt"
+	"runtime"
+	"slices"
 	"testing"
 	"time"
 
@@ -23,6 +26,19 @@ func MeasureExecutionTime(fn func()) time.Duration {
 	return time.Since(start)
 }
 
+// MeasureAllocations measures the heap memory allocated while executing a function.
+// It returns the number of bytes and allocations, taken from runtime.MemStats before and after the call, with a
+// garbage collection beforehand so that earlier work does not skew the result. Allocations made concurrently by
+// other goroutines are included, so it is best used from a quiet program or test.
+func MeasureAllocations(fn func()) (bytes uint64, allocs uint64) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc, after.Mallocs - before.Mallocs
+}
+
 // BenchmarkWrapper executes a function multiple times and returns the average execution time per iteration in nanoseconds.
 // If iterations is less than 1, it returns 0 and an error. If the wrapped function returns an error, it is propagated.
 func BenchmarkWrapper(fn func() error, iterations int) (float64, error) {
@@ -40,6 +56,51 @@ func BenchmarkWrapper(fn func() error, iterations int) (float64, error) {
 	return float64(total.Nanoseconds()) / float64(iterations), nil
 }
 
+// BenchmarkStats summarizes the per-iteration durations recorded by BenchmarkWrapperStats.
+// Percentiles use the nearest-rank method, so each is one of the recorded durations.
+type BenchmarkStats struct {
+	Iterations int
+	Min        time.Duration
+	Max        time.Duration
+	Mean       time.Duration
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+}
+
+// BenchmarkWrapperStats executes a function multiple times like BenchmarkWrapper, but returns the minimum, maximum,
+// mean and percentile durations per iteration, which expose tail latency that an average hides.
+// If iterations is less than 1, it returns zero stats and an error. If the wrapped function returns an error, it is propagated.
+func BenchmarkWrapperStats(fn func() error, iterations int) (BenchmarkStats, error) {
+	if iterations < 1 {
+		return BenchmarkStats{}, fmt.Errorf("iterations must be at least 1, got %d", iterations)
+	}
+	durations := make([]time.Duration, iterations)
+	var total time.Duration
+	for i := range durations {
+		start := time.Now()
+		if err := fn(); err != nil {
+			return BenchmarkStats{}, fmt.Errorf("function failed: %w", err)
+		}
+		durations[i] = time.Since(start)
+		total += durations[i]
+	}
+	slices.Sort(durations)
+	percentile := func(p int) time.Duration {
+		rank := (p*iterations + 99) / 100 // ceil(p/100 * iterations)
+		return durations[max(rank, 1)-1]
+	}
+	return BenchmarkStats{
+		Iterations: iterations,
+		Min:        durations[0],
+		Max:        durations[iterations-1],
+		Mean:       total / time.Duration(iterations),
+		P50:        percentile(50),
+		P95:        percentile(95),
+		P99:        percentile(99),
+	}, nil
+}
+
 // BenchmarkCSVMarshal benchmarks the csv.Marshal function.
 func BenchmarkCSVMarshal(b *testing.B) {
 	records := [][]string{{"name", "age"}, {"Alice", "30"}, {"Bob", "25"}}
@@ -3,6 +3,7 @@ package performance_test
 import (
 	"errors"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -22,6 +23,64 @@ func TestMeasureExecutionTime(t *testing.T) {
 	}
 }
 
+var allocSink []byte
+
+func TestMeasureAllocations(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func()
+		wantBytes  uint64 // Minimum
+		wantAllocs uint64 // Minimum
+		wantMax    uint64 // Maximum allocations, 0 for no limit
+	}{
+		{
+			name:    "No allocations",
+			fn:      func() {},
+			wantMax: 2,
+		},
+		{
+			name: "Single large allocation",
+			fn: func() {
+				allocSink = make([]byte, 1<<20)
+			},
+			wantBytes:  1 << 20,
+			wantAllocs: 1,
+		},
+		{
+			name: "Many allocations",
+			fn: func() {
+				for i := 0; i < 100; i++ {
+					allocSink = make([]byte, 64)
+				}
+			},
+			wantBytes:  100 * 64,
+			wantAllocs: 100,
+		},
+		{
+			name: "CSV Marshal",
+			fn: func() {
+				csv.Marshal([][]string{{"name", "age"}, {"Alice", "30"}})
+			},
+			wantAllocs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bytes, allocs := performance.MeasureAllocations(tt.fn)
+			if bytes < tt.wantBytes {
+				t.Errorf("MeasureAllocations() bytes = %d, want >= %d", bytes, tt.wantBytes)
+			}
+			if allocs < tt.wantAllocs {
+				t.Errorf("MeasureAllocations() allocs = %d, want >= %d", allocs, tt.wantAllocs)
+			}
+			if tt.wantMax > 0 && allocs > tt.wantMax {
+				t.Errorf("MeasureAllocations() allocs = %d, want <= %d", allocs, tt.wantMax)
+			}
+		})
+	}
+}
+
 func TestBenchmarkWrapper(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -112,6 +171,89 @@ func TestBenchmarkWrapper(t *testing.T) {
 	}
 }
 
+func TestBenchmarkWrapperStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func() error
+		iterations int
+		wantErr    bool
+	}{
+		{
+			name: "Valid function",
+			fn: func() error {
+				time.Sleep(100 * time.Microsecond)
+				return nil
+			},
+			iterations: 20,
+			wantErr:    false,
+		},
+		{
+			name:       "Single iteration",
+			fn:         func() error { return nil },
+			iterations: 1,
+			wantErr:    false,
+		},
+		{
+			name:       "Zero iterations",
+			fn:         func() error { return nil },
+			iterations: 0,
+			wantErr:    true,
+		},
+		{
+			name:       "Function with error",
+			fn:         func() error { return errors.New("test error") },
+			iterations: 10,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := performance.BenchmarkWrapperStats(tt.fn, tt.iterations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BenchmarkWrapperStats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if stats != (performance.BenchmarkStats{}) {
+					t.Errorf("BenchmarkWrapperStats() stats = %+v, want zero on error", stats)
+				}
+				return
+			}
+			if stats.Iterations != tt.iterations {
+				t.Errorf("BenchmarkWrapperStats() Iterations = %d, want %d", stats.Iterations, tt.iterations)
+			}
+			ordered := []time.Duration{stats.Min, stats.P50, stats.P95, stats.P99, stats.Max}
+			if !slices.IsSorted(ordered) {
+				t.Errorf("BenchmarkWrapperStats() stats not ordered: %+v", stats)
+			}
+			if stats.Mean < stats.Min || stats.Mean > stats.Max {
+				t.Errorf("BenchmarkWrapperStats() Mean = %v, want between %v and %v", stats.Mean, stats.Min, stats.Max)
+			}
+		})
+	}
+
+	t.Run("Tail latency", func(t *testing.T) {
+		i := 0
+		stats, err := performance.BenchmarkWrapperStats(func() error {
+			i++
+			if i == 100 {
+				time.Sleep(20 * time.Millisecond)
+			}
+			return nil
+		}, 100)
+		if err != nil {
+			t.Fatalf("BenchmarkWrapperStats() unexpected error = %v", err)
+		}
+		if stats.Max < 20*time.Millisecond {
+			t.Errorf("BenchmarkWrapperStats() Max = %v, want >= 20ms", stats.Max)
+		}
+		if stats.P99 >= 20*time.Millisecond || stats.P50 >= 20*time.Millisecond {
+			t.Errorf("BenchmarkWrapperStats() P50 = %v, P99 = %v, want single outlier excluded", stats.P50, stats.P99)
+		}
+	})
+}
+
 func TestBenchmarkCSVMarshalLogic(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1,6 +1,9 @@
-// Package random provides utilities for generating random strings, integers, floats, booleans, hex, base64, UUIDs, and choices.
+// Package random provides utilities for generating random strings, integers, floats, booleans, hex, base64, UUIDs,
+// IP addresses, timestamps, and choices.
 //
 // This package uses crypto/rand for cryptographically secure randomness, making it suitable for security-sensitive applications.
+// For reproducible, non-security use such as tests, the Generator type offers the same functions backed by a seeded
+// math/rand/v2 source.
 // It handles edge cases with appropriate error returns for invalid inputs or randomness generation failures.
 // All functions are designed to be easy to use and integrate with other devify-utils packages.
 package random
@@ -12,6 +15,12 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"math/bits"
+	mathrand "math/rand/v2"
+	"net"
+	"net/netip"
+	"reflect"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -95,6 +104,42 @@ func Int(min, max int) (int, error) {
 	return int(n.Int64()) + min, nil
 }
 
+// Int64 generates a random int64 in the range [min, max] (inclusive) using crypto/rand.
+//
+// Unlike Int, the range is computed with big.Int arithmetic, so the full int64 span (e.g., [math.MinInt64, math.MaxInt64])
+// is supported on all platforms without overflow. The function ensures that min is less than or equal to max,
+// returning an error if this condition is not met.
+//
+// Example:
+//
+//	n, err := Int64(0, math.MaxInt64)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(n) // Prints a random non-negative int64, e.g., 5577006791947779410
+//
+// Parameters:
+//   - min: The minimum value of the range (inclusive).
+//   - max: The maximum value of the range (inclusive).
+//
+// Returns:
+//   - int64: A random int64 in the range [min, max].
+//   - error: An error if min > max or if randomness generation fails.
+func Int64(min, max int64) (int64, error) {
+	if min > max {
+		return 0, fmt.Errorf("min (%d) must be less than or equal to max (%d)", min, max)
+	}
+	// Calculate the range (max - min + 1) without overflowing int64
+	rangeBig := new(big.Int).Sub(big.NewInt(max), big.NewInt(min))
+	rangeBig.Add(rangeBig, big.NewInt(1))
+	n, err := rand.Int(rand.Reader, rangeBig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random number: %w", err)
+	}
+	// Shift the result to [min, max]
+	return n.Add(n, big.NewInt(min)).Int64(), nil
+}
+
 // Hex generates a random hexadecimal string of n characters (0-9, a-f) using crypto/rand.
 //
 // The function ensures that n is non-negative and generates the required number of random bytes,
@@ -198,6 +243,268 @@ func UUID() (string, error) {
 	return id.String(), nil
 }
 
+// UUIDv7 generates a time-ordered UUID (version 7) in the format 8-4-4-4-12 (e.g., "0190163d-8694-739b-aea5-966c26f8ad91").
+//
+// The first 48 bits hold the Unix timestamp in milliseconds, followed by a sub-millisecond sequence that keeps
+// UUIDs generated by this process monotonically increasing, with the remaining bits filled from crypto/rand.
+// Because UUIDs sort lexicographically in creation order, they are well suited as database primary keys, avoiding
+// the index fragmentation caused by random version 4 UUIDs. An error is returned if UUID generation fails.
+//
+// Example:
+//
+//	id, err := UUIDv7()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(id) // Prints a time-ordered UUID, e.g., "0190163d-8694-739b-aea5-966c26f8ad91"
+//
+// Returns:
+//   - string: A time-ordered UUID string.
+//   - error: An error if UUID generation fails.
+func UUIDv7() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	return id.String(), nil
+}
+
+// IPv4 generates a random IPv4 address using crypto/rand.
+//
+// If no CIDR is provided, any address in 0.0.0.0/0 may be returned, including reserved and private ranges.
+// If a CIDR such as "10.0.0.0/8" is provided, the network bits are taken from it and only the host bits are
+// randomized, so the result always falls within that network (network and broadcast addresses included).
+// An error is returned if the CIDR is invalid, is not an IPv4 network, or randomness generation fails.
+//
+// Example:
+//
+//	ip, err := IPv4("192.168.0.0/16")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ip) // Prints a random address in the network, e.g., "192.168.41.7"
+//
+// Parameters:
+//   - cidr: Optional IPv4 network in CIDR notation to constrain the address to. Only the first value is used.
+//
+// Returns:
+//   - string: A random IPv4 address in dotted decimal notation.
+//   - error: An error if the CIDR is invalid or randomness generation fails.
+func IPv4(cidr ...string) (string, error) {
+	return randomIP(net.IPv4len, cidr)
+}
+
+// IPv6 generates a random IPv6 address using crypto/rand.
+//
+// It behaves like IPv4, but for 128-bit addresses: if a CIDR such as "2001:db8::/32" is provided, only the host
+// bits are randomized. The result is formatted in the canonical compressed form (RFC 5952).
+//
+// Example:
+//
+//	ip, err := IPv6("2001:db8::/32")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ip) // Prints a random address in the network, e.g., "2001:db8:5e1f:9a2::c04"
+//
+// Parameters:
+//   - cidr: Optional IPv6 network in CIDR notation to constrain the address to. Only the first value is used.
+//
+// Returns:
+//   - string: A random IPv6 address.
+//   - error: An error if the CIDR is invalid or randomness generation fails.
+func IPv6(cidr ...string) (string, error) {
+	return randomIP(net.IPv6len, cidr)
+}
+
+// randomIP generates a random address of the given byte size, keeping the network bits of the optional CIDR.
+func randomIP(size int, cidr []string) (string, error) {
+	family := "IPv4"
+	if size == net.IPv6len {
+		family = "IPv6"
+	}
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	if len(cidr) > 0 {
+		prefix, err := netip.ParsePrefix(cidr[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid CIDR %q: %w", cidr[0], err)
+		}
+		if prefix.Addr().BitLen() != size*8 || prefix.Addr().Is4In6() {
+			return "", fmt.Errorf("CIDR %q is not an %s network", cidr[0], family)
+		}
+		network := prefix.Masked().Addr().AsSlice()
+		for i, remaining := 0, prefix.Bits(); i < size && remaining > 0; i, remaining = i+1, remaining-8 {
+			mask := byte(0xff)
+			if remaining < 8 {
+				mask = ^byte(0xff >> remaining)
+			}
+			b[i] = network[i]&mask | b[i]&^mask
+		}
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr.String(), nil
+}
+
+// nanoIDAlphabet is the default URL-safe alphabet used by NanoID.
+const nanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// NanoID generates a compact, URL-safe random identifier of size characters using crypto/rand.
+//
+// By default the 64-character URL-safe alphabet (A-Z, a-z, 0-9, '_' and '-') is used; a size of 21 gives
+// collision resistance comparable to a version 4 UUID. An optional custom alphabet of up to 256 characters may be provided.
+// Random bytes are masked to the smallest power of two covering the alphabet and out-of-range values are rejected,
+// so every character is equally likely (no modulo bias). An error is returned if size is negative, the custom
+// alphabet is empty or longer than 256 characters, or randomness generation fails.
+//
+// Example:
+//
+//	id, err := NanoID(21)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(id) // Prints a random 21-character ID, e.g., "V1StGXR8_Z5jdHi6B-myT"
+//	hexID, _ := NanoID(12, "0123456789abcdef")
+//	fmt.Println(hexID) // Prints a random 12-character lowercase hex ID, e.g., "4f90d13a42bc"
+//
+// Parameters:
+//   - size: The length of the ID to generate.
+//   - alphabet: Optional string of characters to use. If not provided, defaults to the URL-safe alphabet.
+//
+// Returns:
+//   - string: A random ID of length size.
+//   - error: An error if size is negative, the alphabet is empty or too long, or randomness generation fails.
+func NanoID(size int, alphabet ...string) (string, error) {
+	if size < 0 {
+		return "", fmt.Errorf("size must be non-negative, got %d", size)
+	}
+	chars := []rune(nanoIDAlphabet)
+	if len(alphabet) > 0 {
+		chars = []rune(alphabet[0])
+	}
+	if len(chars) == 0 || len(chars) > 256 {
+		return "", fmt.Errorf("alphabet must contain between 1 and 256 characters, got %d", len(chars))
+	}
+	// Mask random bytes to the smallest power of two covering the alphabet, then reject out-of-range values
+	mask := (1 << bits.Len(uint(len(chars)-1))) - 1
+	step := int(math.Ceil(1.6 * float64(mask) * float64(size) / float64(len(chars))))
+	if step < 1 {
+		step = 1
+	}
+	id := make([]rune, 0, size)
+	buf := make([]byte, step)
+	for len(id) < size {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		for _, b := range buf {
+			idx := int(b) & mask
+			if idx >= len(chars) {
+				continue
+			}
+			id = append(id, chars[idx])
+			if len(id) == size {
+				break
+			}
+		}
+	}
+	return string(id), nil
+}
+
+// readableAlphabet excludes easily confused characters: 0/O/o and 1/l/I.
+const readableAlphabet = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// ReadableToken generates a random token of n characters that avoids easily confused characters, using crypto/rand.
+//
+// The token is drawn from letters and digits excluding '0', 'O', 'o', '1', 'l', and 'I', so it can be read aloud
+// or typed by hand without ambiguity (e.g., for recovery codes or one-time passwords). Characters are selected
+// without modulo bias using NanoID. An error is returned if n is not positive or randomness generation fails.
+//
+// Example:
+//
+//	token, err := ReadableToken(8)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(token) // Prints a random 8-character token, e.g., "h7RkM3xq"
+//
+// Parameters:
+//   - n: The length of the token to generate (must be positive).
+//
+// Returns:
+//   - string: A random token of length n.
+//   - error: An error if n is not positive or randomness generation fails.
+func ReadableToken(n int) (string, error) {
+	if n <= 0 {
+		return "", fmt.Errorf("length must be positive, got %d", n)
+	}
+	return NanoID(n, readableAlphabet)
+}
+
+// qrAlphanumericAlphabet is the 45-character set supported by the QR code alphanumeric encoding mode.
+const qrAlphanumericAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// QRAlphanumeric generates a random string of n characters from the QR code alphanumeric character set, using crypto/rand.
+//
+// The string is drawn from exactly the 45 characters allowed by the QR alphanumeric mode ('0'-'9', 'A'-'Z', space,
+// and "$%*+-./:"), so the resulting payload can be encoded more compactly than in byte mode. Characters are selected
+// without modulo bias using NanoID. An error is returned if n is not positive or randomness generation fails.
+//
+// Example:
+//
+//	code, err := QRAlphanumeric(10)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(code) // Prints a random 10-character string, e.g., "K7$Q2 ./ZA"
+//
+// Parameters:
+//   - n: The length of the string to generate (must be positive).
+//
+// Returns:
+//   - string: A random string of length n using only QR alphanumeric characters.
+//   - error: An error if n is not positive or randomness generation fails.
+func QRAlphanumeric(n int) (string, error) {
+	if n <= 0 {
+		return "", fmt.Errorf("length must be positive, got %d", n)
+	}
+	return NanoID(n, qrAlphanumericAlphabet)
+}
+
+// crockfordAlphabet is Crockford's Base32 alphabet, which excludes I, L, O and U.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Base32Crockford generates a random string of n characters from Crockford's Base32 alphabet, using crypto/rand.
+//
+// The alphabet consists of the digits and the uppercase letters except 'I', 'L', 'O' and 'U', so the result avoids
+// characters that are easily confused with '1' and '0' and is easy to read aloud and type (e.g., for coupon codes
+// or device pairing codes). Each character carries 5 bits of randomness, and characters are selected without
+// modulo bias using NanoID. When decoding user input, Crockford's scheme maps 'I' and 'L' to '1' and 'O' to '0'
+// and is case-insensitive. An error is returned if n is negative or randomness generation fails.
+//
+// Example:
+//
+//	code, err := Base32Crockford(8)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(code) // Prints a random 8-character code, e.g., "7ZK3M9QT"
+//
+// Parameters:
+//   - n: The length of the string to generate (must not be negative).
+//
+// Returns:
+//   - string: A random string of length n using only Crockford Base32 characters, or an empty string if n is 0.
+//   - error: An error if n is negative or randomness generation fails.
+func Base32Crockford(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("length must not be negative, got %d", n)
+	}
+	return NanoID(n, crockfordAlphabet)
+}
+
 // Float64 generates a random float64 in the range [min, max] using crypto/rand.
 //
 // The function ensures that min is less than or equal to max and that both values are finite and not NaN.
@@ -243,6 +550,93 @@ func Float64(min, max float64) (float64, error) {
 	return min + fraction*(max-min), nil
 }
 
+// Float64Prec generates a random float64 in the range [min, max] rounded to the given number of decimal places,
+// using crypto/rand.
+//
+// The value is generated with Float64, so the same NaN, infinity and ordering checks apply, and is then rounded half
+// away from zero (e.g., for prices or percentages). Results are kept within [min, max]: a value that would round
+// past a bound is clamped to the nearest value with the requested precision inside the range. As with any float64,
+// the result is the closest representable value to the decimal (e.g., 0.37 is stored as 0.36999999999999999556).
+// If decimals exceeds the precision a float64 can hold for the value, it is returned unrounded.
+//
+// Example:
+//
+//	price, err := Float64Prec(1.0, 100.0, 2)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(price) // Prints a random price with at most two decimal places, e.g., 42.37
+//
+// Parameters:
+//   - min: The minimum value of the range (inclusive).
+//   - max: The maximum value of the range (inclusive).
+//   - decimals: The number of decimal places to round to (must not be negative).
+//
+// Returns:
+//   - float64: A random float64 in the range [min, max] with at most decimals decimal places.
+//   - error: An error if decimals is negative, the range is invalid or contains no value with the requested
+//     precision, or randomness generation fails.
+func Float64Prec(min, max float64, decimals int) (float64, error) {
+	if decimals < 0 {
+		return 0, fmt.Errorf("decimals must not be negative, got %d", decimals)
+	}
+	f, err := Float64(min, max)
+	if err != nil {
+		return 0, err
+	}
+	pow := math.Pow10(decimals)
+	scaled := f * pow
+	if math.IsInf(scaled, 0) || math.Abs(scaled) >= 1<<53 {
+		return f, nil // Rounding would not change the value
+	}
+	lo, hi := math.Ceil(min*pow)/pow, math.Floor(max*pow)/pow
+	if lo > hi {
+		return 0, fmt.Errorf("range [%g, %g] contains no value with %d decimal places", min, max, decimals)
+	}
+	return math.Min(math.Max(math.Round(scaled)/pow, lo), hi), nil
+}
+
+// TimeBetween generates a uniformly random time in the range [start, end] (inclusive) using crypto/rand.
+//
+// The time is drawn with nanosecond resolution over the span between start and end, computed with big.Int arithmetic
+// so that spans longer than time.Duration can hold (about 292 years) are supported. The result is in the location
+// of start and carries no monotonic clock reading. This is useful for generating timestamps when seeding test data.
+// An error is returned if end is before start or randomness generation fails.
+//
+// Example:
+//
+//	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+//	t, err := TimeBetween(start, start.AddDate(1, 0, 0))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(t) // Prints a random time in 2024, e.g., 2024-07-19 04:12:55.123456789 +0000 UTC
+//
+// Parameters:
+//   - start: The earliest time of the range (inclusive).
+//   - end: The latest time of the range (inclusive).
+//
+// Returns:
+//   - time.Time: A random time in the range [start, end].
+//   - error: An error if end is before start or if randomness generation fails.
+func TimeBetween(start, end time.Time) (time.Time, error) {
+	if end.Before(start) {
+		return time.Time{}, fmt.Errorf("end (%s) must not be before start (%s)", end, start)
+	}
+	// Calculate the span in nanoseconds (end - start + 1) without overflowing int64
+	nanosPerSecond := big.NewInt(int64(time.Second))
+	span := new(big.Int).Sub(big.NewInt(end.Unix()), big.NewInt(start.Unix()))
+	span.Mul(span, nanosPerSecond)
+	span.Add(span, big.NewInt(int64(end.Nanosecond()-start.Nanosecond()+1)))
+	n, err := rand.Int(rand.Reader, span)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to generate random number: %w", err)
+	}
+	// Offset start by the random number of nanoseconds, split into seconds and nanoseconds
+	seconds, nanos := new(big.Int).QuoRem(n, nanosPerSecond, new(big.Int))
+	return time.Unix(start.Unix()+seconds.Int64(), int64(start.Nanosecond())+nanos.Int64()).In(start.Location()), nil
+}
+
 // Alphanumeric generates a random alphanumeric string of n characters (A-Z, a-z, 0-9) using crypto/rand.
 //
 // The function is a convenience wrapper around String, using a predefined alphanumeric character set.
@@ -320,3 +714,432 @@ func Choice(items []string) (string, error) {
 	}
 	return items[idx], nil
 }
+
+// WeightedChoice selects a random element from a slice of strings with probability proportional to its weight using crypto/rand.
+//
+// Each item is paired with the weight at the same index. Items with a weight of 0 are never selected.
+// An error is returned if the slices differ in length, any weight is negative, the total weight is zero,
+// or randomness generation fails.
+//
+// Example:
+//
+//	backends := []string{"primary", "canary"}
+//	choice, err := WeightedChoice(backends, []int{9, 1})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(choice) // Prints "primary" ~90% of the time and "canary" ~10% of the time
+//
+// Parameters:
+//   - items: A slice of strings to choose from.
+//   - weights: A slice of non-negative weights, one per item.
+//
+// Returns:
+//   - string: A randomly selected string from the input slice.
+//   - error: An error if the slices differ in length, a weight is negative, the total weight is zero, or randomness generation fails.
+func WeightedChoice(items []string, weights []int) (string, error) {
+	if len(items) != len(weights) {
+		return "", fmt.Errorf("items and weights must have the same length, got %d and %d", len(items), len(weights))
+	}
+	var total int64
+	for i, w := range weights {
+		if w < 0 {
+			return "", fmt.Errorf("weight at index %d must be non-negative, got %d", i, w)
+		}
+		total += int64(w)
+	}
+	if total == 0 {
+		return "", fmt.Errorf("total weight must be greater than zero")
+	}
+	n, err := Int64(0, total-1)
+	if err != nil {
+		return "", fmt.Errorf("failed to select random weight: %w", err)
+	}
+	for i, w := range weights {
+		if n < int64(w) {
+			return items[i], nil
+		}
+		n -= int64(w)
+	}
+	return items[len(items)-1], nil
+}
+
+// Shuffle randomly permutes a slice of strings in place using crypto/rand.
+//
+// The function performs a Fisher-Yates shuffle, using the Int function to select each swap index, so every
+// permutation is equally likely. Empty and single-element slices are left unchanged.
+// An error is returned if randomness generation fails.
+//
+// Example:
+//
+//	items := []string{"apple", "banana", "cherry"}
+//	if err := Shuffle(items); err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(items) // Prints a random permutation, e.g., [cherry apple banana]
+//
+// Parameters:
+//   - items: The slice of strings to shuffle in place.
+//
+// Returns:
+//   - error: An error if randomness generation fails.
+func Shuffle(items []string) error {
+	for i := len(items) - 1; i > 0; i-- {
+		j, err := Int(0, i)
+		if err != nil {
+			return fmt.Errorf("failed to select random index: %w", err)
+		}
+		items[i], items[j] = items[j], items[i]
+	}
+	return nil
+}
+
+// Sample selects k distinct elements from a slice of strings without replacement using crypto/rand.
+//
+// The function performs a partial Fisher-Yates shuffle on a copy of items, so the input slice is not modified
+// and the returned elements are in random order. Elements are distinct by position; duplicate values in items
+// may therefore appear more than once in the result. An error is returned if k is negative, k exceeds the
+// number of items, or randomness generation fails.
+//
+// Example:
+//
+//	servers := []string{"a", "b", "c", "d"}
+//	picked, err := Sample(servers, 2)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(picked) // Prints 2 random distinct servers, e.g., [d a]
+//
+// Parameters:
+//   - items: A slice of strings to sample from.
+//   - k: The number of elements to select.
+//
+// Returns:
+//   - []string: A new slice of k randomly selected elements in random order.
+//   - error: An error if k is negative, k exceeds len(items), or randomness generation fails.
+func Sample(items []string, k int) ([]string, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("k must be non-negative, got %d", k)
+	}
+	if k > len(items) {
+		return nil, fmt.Errorf("k (%d) must be less than or equal to the number of items (%d)", k, len(items))
+	}
+	pool := make([]string, len(items))
+	copy(pool, items)
+	for i := 0; i < k; i++ {
+		j, err := Int(i, len(pool)-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select random index: %w", err)
+		}
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:k:k], nil
+}
+
+// FailureInjector returns a function that reports true with probability rate on each call, using crypto/rand.
+//
+// The returned function is intended for chaos and resilience testing: wrap an operation and simulate an
+// intermittent failure whenever it returns true. A rate of 0 never injects failures and a rate of 1 always does.
+// If randomness generation fails during a call, the function returns false (no failure injected).
+// An error is returned if rate is NaN or outside the range [0, 1].
+//
+// Example:
+//
+//	shouldFail, err := FailureInjector(0.1)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if shouldFail() {
+//	    return errors.New("injected failure") // Happens ~10% of the time
+//	}
+//
+// Parameters:
+//   - rate: The probability, in the range [0, 1], that each call returns true.
+//
+// Returns:
+//   - func() bool: A function returning true with probability rate.
+//   - error: An error if rate is NaN or outside [0, 1].
+func FailureInjector(rate float64) (func() bool, error) {
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("rate must be in the range [0, 1], got %f", rate)
+	}
+	// Compare a uniform 53-bit integer against the scaled rate
+	const precision = 1 << 53
+	threshold := int64(rate * precision)
+	maxInt := big.NewInt(precision)
+	return func() bool {
+		n, err := rand.Int(rand.Reader, maxInt)
+		if err != nil {
+			return false
+		}
+		return n.Int64() < threshold
+	}, nil
+}
+
+// maxFillDepth limits how deeply FillStruct follows pointers and slices, so self-referential types terminate.
+const maxFillDepth = 8
+
+// FillStruct populates the exported fields of the struct pointed to by v with random values using crypto/rand.
+//
+// Strings are filled with 8 random alphanumeric characters, integers and unsigned integers with values in [1, 100],
+// floats with values in [1.0, 100.0], and booleans randomly. Nested structs, arrays, and pointers are filled recursively,
+// and slices are given between 1 and 5 randomly filled elements. Unexported fields are skipped, and pointers and slices
+// nested deeper than 8 levels are left as nil. Fields of unsupported kinds (maps, channels, funcs, interfaces, complex
+// numbers, and unsafe pointers) cause an error to be returned. This is useful for property-based testing.
+//
+// Example:
+//
+//	type User struct {
+//	    Name  string
+//	    Age   int
+//	    Admin bool
+//	}
+//	var u User
+//	if err := FillStruct(&u); err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(u) // Prints a random user, e.g., {aB7xY9pQ 42 true}
+//
+// Parameters:
+//   - v: A non-nil pointer to the struct to populate.
+//
+// Returns:
+//   - error: An error if v is not a non-nil pointer to a struct, a field has an unsupported type, or randomness generation fails.
+func FillStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("v must be a non-nil pointer to a struct")
+	}
+	return fillValue(rv.Elem(), 0)
+}
+
+// fillValue sets v to a random value appropriate to its kind, recursing into structs, arrays, slices, and pointers.
+func fillValue(v reflect.Value, depth int) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(Alphanumeric(8))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := Int(1, 100)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := Int(1, 100)
+		if err != nil {
+			return err
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := Float64(1, 100)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := Boolean()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := fillValue(v.Field(i), depth); err != nil {
+				return fmt.Errorf("field %s: %w", t.Field(i).Name, err)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := fillValue(v.Index(i), depth); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if depth >= maxFillDepth {
+			return nil
+		}
+		n, err := Int(1, 5)
+		if err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := fillValue(s.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Pointer:
+		if depth >= maxFillDepth {
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := fillValue(p.Elem(), depth+1); err != nil {
+			return err
+		}
+		v.Set(p)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Generator produces deterministic pseudo-random values from a seed, for reproducible tests.
+//
+// Its methods mirror the package-level functions (String, Int, Choice, Shuffle, etc.) with the same signatures and
+// validation, but draw from a math/rand/v2 PCG source instead of crypto/rand, so the same seed always yields the same
+// sequence of values. A Generator is NOT suitable for security-sensitive use (tokens, passwords, keys); use the
+// package-level functions for those. A Generator is not safe for concurrent use.
+type Generator struct {
+	rng *mathrand.Rand
+}
+
+// NewGenerator creates a new Generator seeded deterministically with seed.
+//
+// Example:
+//
+//	g := NewGenerator(42)
+//	n, _ := g.Int(1, 10)
+//	fmt.Println(n) // Prints the same value on every run for seed 42
+//
+// Parameters:
+//   - seed: The seed for the pseudo-random sequence.
+//
+// Returns:
+//   - *Generator: A pointer to the initialized Generator instance.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rng: mathrand.New(mathrand.NewPCG(uint64(seed), 0x9E3779B97F4A7C15))}
+}
+
+// String generates a pseudo-random string of n characters, as String does.
+func (g *Generator) String(n int, validCharacters ...string) string {
+	if n < 0 {
+		return ""
+	}
+	chars := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890_+")
+	if len(validCharacters) > 0 {
+		chars = []rune(validCharacters[0])
+	}
+	if len(chars) == 0 {
+		return ""
+	}
+	s := make([]rune, n)
+	for i := range s {
+		s[i] = chars[g.rng.IntN(len(chars))]
+	}
+	return string(s)
+}
+
+// Alphanumeric generates a pseudo-random alphanumeric string of n characters, as Alphanumeric does.
+func (g *Generator) Alphanumeric(n int) string {
+	return g.String(n, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+}
+
+// Hex generates a pseudo-random hexadecimal string of n characters, as Hex does.
+func (g *Generator) Hex(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("length must be non-negative, got %d", n)
+	}
+	return g.String(n, "0123456789abcdef"), nil
+}
+
+// Int generates a pseudo-random integer in the range [min, max] (inclusive), as Int does.
+func (g *Generator) Int(min, max int) (int, error) {
+	n, err := g.Int64(int64(min), int64(max))
+	return int(n), err
+}
+
+// Int64 generates a pseudo-random int64 in the range [min, max] (inclusive), as Int64 does.
+func (g *Generator) Int64(min, max int64) (int64, error) {
+	if min > max {
+		return 0, fmt.Errorf("min (%d) must be less than or equal to max (%d)", min, max)
+	}
+	span := uint64(max-min) + 1
+	if span == 0 {
+		// The range covers all of int64
+		return int64(g.rng.Uint64()), nil
+	}
+	return min + int64(g.rng.Uint64N(span)), nil
+}
+
+// Float64 generates a pseudo-random float64 in the range [min, max), with the same checks as Float64. Unlike the
+// package-level Float64, which includes max, max is excluded (min is returned when min equals max).
+func (g *Generator) Float64(min, max float64) (float64, error) {
+	if min > max {
+		return 0, fmt.Errorf("min (%f) must be less than or equal to max (%f)", min, max)
+	}
+	if math.IsNaN(min) || math.IsNaN(max) {
+		return 0, fmt.Errorf("min and max must not be NaN")
+	}
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return 0, fmt.Errorf("min and max must be finite")
+	}
+	return min + g.rng.Float64()*(max-min), nil
+}
+
+// Boolean generates a pseudo-random boolean, as Boolean does. The error is always nil.
+func (g *Generator) Boolean() (bool, error) {
+	return g.rng.IntN(2) == 1, nil
+}
+
+// Choice selects a pseudo-random element from a slice of strings, as Choice does.
+func (g *Generator) Choice(items []string) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("items slice is empty")
+	}
+	return items[g.rng.IntN(len(items))], nil
+}
+
+// WeightedChoice selects a pseudo-random element with probability proportional to its weight, as WeightedChoice does.
+func (g *Generator) WeightedChoice(items []string, weights []int) (string, error) {
+	if len(items) != len(weights) {
+		return "", fmt.Errorf("items and weights must have the same length, got %d and %d", len(items), len(weights))
+	}
+	var total int64
+	for i, w := range weights {
+		if w < 0 {
+			return "", fmt.Errorf("weight at index %d must be non-negative, got %d", i, w)
+		}
+		total += int64(w)
+	}
+	if total == 0 {
+		return "", fmt.Errorf("total weight must be greater than zero")
+	}
+	n := g.rng.Int64N(total)
+	for i, w := range weights {
+		if n < int64(w) {
+			return items[i], nil
+		}
+		n -= int64(w)
+	}
+	return items[len(items)-1], nil
+}
+
+// Shuffle pseudo-randomly permutes a slice of strings in place, as Shuffle does. The error is always nil.
+func (g *Generator) Shuffle(items []string) error {
+	g.rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	return nil
+}
+
+// Sample selects k distinct elements from a slice of strings without replacement, as Sample does.
+func (g *Generator) Sample(items []string, k int) ([]string, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("k must be non-negative, got %d", k)
+	}
+	if k > len(items) {
+		return nil, fmt.Errorf("k (%d) must be less than or equal to the number of items (%d)", k, len(items))
+	}
+	pool := make([]string, len(items))
+	copy(pool, items)
+	for i := 0; i < k; i++ {
+		j := i + g.rng.IntN(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:k:k], nil
+}
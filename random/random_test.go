@@ -2,8 +2,13 @@ package random_test
 
 import (
 	"math"
+	"net"
+	"reflect"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/devify-me/devify-utils/random"
 )
@@ -77,6 +82,49 @@ func TestInt(t *testing.T) {
 	}
 }
 
+func TestInt64(t *testing.T) {
+	tests := []struct {
+		name     string
+		min      int64
+		max      int64
+		wantErr  bool
+		checkRun int
+	}{
+		{"happy: min=max", 5, 5, false, 1},
+		{"happy: range", 1, 10, false, 100},
+		{"happy: up to MaxInt64", 0, math.MaxInt64, false, 100},
+		{"happy: full span", math.MinInt64, math.MaxInt64, false, 100},
+		{"happy: extremes equal", math.MaxInt64, math.MaxInt64, false, 1},
+		{"edge: min>max", 10, 1, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < tt.checkRun; i++ {
+				got, err := random.Int64(tt.min, tt.max)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Int64() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !tt.wantErr && (got < tt.min || got > tt.max) {
+					t.Errorf("Int64() = %d, out of range [%d, %d]", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+	// Values above the 32-bit range should be produced for large spans
+	large := false
+	for i := 0; i < 100; i++ {
+		got, _ := random.Int64(0, math.MaxInt64)
+		if got > math.MaxInt32 {
+			large = true
+			break
+		}
+	}
+	if !large {
+		t.Errorf("Int64() never exceeded MaxInt32 in 100 runs over [0, MaxInt64]")
+	}
+}
+
 func TestHex(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -173,6 +221,272 @@ func TestUUID(t *testing.T) {
 	}
 }
 
+func TestUUIDv7(t *testing.T) {
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	var ids []string
+	for i := 0; i < 100; i++ {
+		got, err := random.UUIDv7()
+		if err != nil {
+			t.Errorf("UUIDv7() error = %v", err)
+		}
+		if !uuidRegex.MatchString(got) {
+			t.Errorf("UUIDv7() = %q, invalid format", got)
+		}
+		ids = append(ids, got)
+	}
+	// UUIDs generated in sequence should sort in creation order without duplicates
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("UUIDv7() not ordered: %q generated after %q", ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestIPv4(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    []string
+		wantErr string
+	}{
+		{"happy: no CIDR", nil, ""},
+		{"happy: /8 network", []string{"10.0.0.0/8"}, ""},
+		{"happy: unaligned /20", []string{"172.16.16.0/20"}, ""},
+		{"happy: non-canonical CIDR", []string{"192.168.1.77/24"}, ""},
+		{"edge: /32 host", []string{"203.0.113.9/32"}, ""},
+		{"edge: /0", []string{"0.0.0.0/0"}, ""},
+		{"edge: invalid CIDR", []string{"10.0.0.0/33"}, "invalid CIDR"},
+		{"edge: IPv6 CIDR", []string{"2001:db8::/32"}, "is not an IPv4 network"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got, err := random.IPv4(tt.cidr...)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("IPv4() error = %v, wantErr containing %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("IPv4() unexpected error = %v", err)
+				}
+				ip := net.ParseIP(got)
+				if ip == nil || ip.To4() == nil || strings.Contains(got, ":") {
+					t.Fatalf("IPv4() = %q, not a valid IPv4 address", got)
+				}
+				if len(tt.cidr) > 0 {
+					_, network, _ := net.ParseCIDR(tt.cidr[0])
+					if !network.Contains(ip) {
+						t.Fatalf("IPv4() = %q, not within %s", got, tt.cidr[0])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestIPv6(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    []string
+		wantErr string
+	}{
+		{"happy: no CIDR", nil, ""},
+		{"happy: /32 network", []string{"2001:db8::/32"}, ""},
+		{"happy: unaligned /61", []string{"fd00:1234:5678:9a8::/61"}, ""},
+		{"edge: /128 host", []string{"2001:db8::1/128"}, ""},
+		{"edge: invalid CIDR", []string{"2001:db8::/129"}, "invalid CIDR"},
+		{"edge: IPv4 CIDR", []string{"10.0.0.0/8"}, "is not an IPv6 network"},
+		{"edge: IPv4-mapped CIDR", []string{"::ffff:10.0.0.0/104"}, "is not an IPv6 network"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got, err := random.IPv6(tt.cidr...)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("IPv6() error = %v, wantErr containing %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("IPv6() unexpected error = %v", err)
+				}
+				ip := net.ParseIP(got)
+				if ip == nil || !strings.Contains(got, ":") {
+					t.Fatalf("IPv6() = %q, not a valid IPv6 address", got)
+				}
+				if len(tt.cidr) > 0 {
+					_, network, _ := net.ParseCIDR(tt.cidr[0])
+					if !network.Contains(ip) {
+						t.Fatalf("IPv6() = %q, not within %s", got, tt.cidr[0])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNanoID(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		alphabet  []string
+		wantLen   int
+		wantRegex *regexp.Regexp
+		wantErr   bool
+	}{
+		{"happy: default", 21, nil, 21, regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`), false},
+		{"happy: custom hex", 12, []string{"0123456789abcdef"}, 12, regexp.MustCompile(`^[0-9a-f]{12}$`), false},
+		{"happy: non power of two", 50, []string{"abc"}, 50, regexp.MustCompile(`^[abc]{50}$`), false},
+		{"happy: single char", 5, []string{"x"}, 5, regexp.MustCompile(`^x{5}$`), false},
+		{"happy: unicode", 8, []string{"αβγ"}, 8, regexp.MustCompile(`^[αβγ]{8}$`), false},
+		{"edge: size=0", 0, nil, 0, nil, false},
+		{"edge: size<0", -1, nil, 0, nil, true},
+		{"edge: empty alphabet", 10, []string{""}, 0, nil, true},
+		{"edge: alphabet too long", 10, []string{strings.Repeat("a", 257)}, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := random.NanoID(tt.size, tt.alphabet...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NanoID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len([]rune(got)) != tt.wantLen {
+				t.Errorf("NanoID() len = %d, want %d", len([]rune(got)), tt.wantLen)
+			}
+			if tt.wantRegex != nil && !tt.wantRegex.MatchString(got) {
+				t.Errorf("NanoID() = %q, does not match regex %s", got, tt.wantRegex)
+			}
+		})
+	}
+	// Variance check
+	set := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		got, _ := random.NanoID(21)
+		if set[got] {
+			t.Errorf("NanoID() duplicate in 100 runs: %q", got)
+		}
+		set[got] = true
+	}
+	// Distribution check: with a 3-character alphabet each character should appear ~1/3 of the time
+	counts := make(map[rune]int)
+	got, _ := random.NanoID(9000, "abc")
+	for _, r := range got {
+		counts[r]++
+	}
+	for _, r := range "abc" {
+		if counts[r] < 2700 || counts[r] > 3300 {
+			t.Errorf("NanoID() character %q appeared %d times in 9000, want ~3000", r, counts[r])
+		}
+	}
+}
+
+func TestReadableToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wantLen int
+		wantErr bool
+	}{
+		{"happy: short", 8, 8, false},
+		{"happy: long", 1000, 1000, false},
+		{"edge: n=0", 0, 0, true},
+		{"edge: n<0", -1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := random.ReadableToken(tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadableToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("ReadableToken() len = %d, want %d", len(got), tt.wantLen)
+			}
+			if strings.ContainsAny(got, "0Oo1lI") {
+				t.Errorf("ReadableToken() = %q, contains a lookalike character", got)
+			}
+		})
+	}
+	// Lookalike check over many characters
+	for i := 0; i < 100; i++ {
+		got, _ := random.ReadableToken(100)
+		if strings.ContainsAny(got, "0Oo1lI") {
+			t.Fatalf("ReadableToken() = %q, contains a lookalike character", got)
+		}
+	}
+}
+
+func TestQRAlphanumeric(t *testing.T) {
+	const qrSet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+	tests := []struct {
+		name    string
+		n       int
+		wantLen int
+		wantErr bool
+	}{
+		{"happy: short", 10, 10, false},
+		{"happy: long", 1000, 1000, false},
+		{"edge: n=1", 1, 1, false},
+		{"edge: n=0", 0, 0, true},
+		{"edge: n<0", -5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := random.QRAlphanumeric(tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QRAlphanumeric() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("QRAlphanumeric() len = %d, want %d", len(got), tt.wantLen)
+			}
+			for _, r := range got {
+				if !strings.ContainsRune(qrSet, r) {
+					t.Errorf("QRAlphanumeric() = %q, contains %q outside the QR alphanumeric set", got, r)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestBase32Crockford(t *testing.T) {
+	const crockfordSet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	tests := []struct {
+		name    string
+		n       int
+		wantLen int
+		wantErr bool
+	}{
+		{"happy: short", 8, 8, false},
+		{"happy: long", 1000, 1000, false},
+		{"edge: n=1", 1, 1, false},
+		{"edge: n=0", 0, 0, false},
+		{"edge: n<0", -5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := random.Base32Crockford(tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Base32Crockford() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("Base32Crockford() len = %d, want %d", len(got), tt.wantLen)
+			}
+			for _, r := range got {
+				if !strings.ContainsRune(crockfordSet, r) {
+					t.Errorf("Base32Crockford() = %q, contains %q outside the Crockford alphabet", got, r)
+					break
+				}
+			}
+		})
+	}
+}
+
 func TestFloat64(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -209,6 +523,91 @@ func TestFloat64(t *testing.T) {
 	}
 }
 
+func TestFloat64Prec(t *testing.T) {
+	tests := []struct {
+		name     string
+		min      float64
+		max      float64
+		decimals int
+		wantErr  bool
+		checkRun int
+	}{
+		{"happy: two decimals", 0.0, 1.0, 2, false, 100},
+		{"happy: zero decimals", 1.0, 10.0, 0, false, 100},
+		{"happy: negative range", -5.0, -1.0, 3, false, 100},
+		{"happy: min=max", 2.5, 2.5, 1, false, 1},
+		{"edge: bounds not at precision", 0.123, 0.137, 2, false, 100},
+		{"edge: high precision", 1e10, 1e10 + 1, 20, false, 10},
+		{"edge: no value at precision", 0.001, 0.004, 2, true, 1},
+		{"edge: negative decimals", 0.0, 1.0, -1, true, 1},
+		{"edge: min>max", 10.0, 1.0, 2, true, 1},
+		{"edge: NaN min", math.NaN(), 10.0, 2, true, 1},
+		{"edge: inf", -math.Inf(1), math.Inf(1), 2, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < tt.checkRun; i++ {
+				got, err := random.Float64Prec(tt.min, tt.max, tt.decimals)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Float64Prec() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				if got < tt.min || got > tt.max {
+					t.Errorf("Float64Prec() = %v, out of range [%v, %v]", got, tt.min, tt.max)
+				}
+				if tt.decimals <= 15 {
+					pow := math.Pow10(tt.decimals)
+					if math.Round(got*pow)/pow != got {
+						t.Errorf("Float64Prec() = %v, has more than %d decimal places", got, tt.decimals)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTimeBetween(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		wantErr  bool
+		checkRun int
+	}{
+		{"happy: one year", base, base.AddDate(1, 0, 0), false, 100},
+		{"happy: one second", base, base.Add(time.Second), false, 100},
+		{"happy: sub-second start", base.Add(999 * time.Millisecond), base.Add(1001 * time.Millisecond), false, 100},
+		{"happy: other location", base.In(time.FixedZone("UTC+2", 2*60*60)), base.Add(time.Hour), false, 10},
+		{"edge: start=end", base, base, false, 1},
+		{"edge: longer than time.Duration", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), false, 100},
+		{"edge: end before start", base, base.Add(-time.Nanosecond), true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < tt.checkRun; i++ {
+				got, err := random.TimeBetween(tt.start, tt.end)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("TimeBetween() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				if got.Before(tt.start) || got.After(tt.end) {
+					t.Errorf("TimeBetween() = %v, out of range [%v, %v]", got, tt.start, tt.end)
+				}
+				if got.Location() != tt.start.Location() {
+					t.Errorf("TimeBetween() location = %v, want %v", got.Location(), tt.start.Location())
+				}
+			}
+		})
+	}
+}
+
 func TestAlphanumeric(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -293,3 +692,338 @@ func TestChoice(t *testing.T) {
 		})
 	}
 }
+
+func TestWeightedChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		weights []int
+		wantErr bool
+	}{
+		{"happy: single", []string{"a"}, []int{1}, false},
+		{"happy: zero weight skipped", []string{"a", "b"}, []int{0, 5}, false},
+		{"edge: length mismatch", []string{"a", "b"}, []int{1}, true},
+		{"edge: negative weight", []string{"a", "b"}, []int{1, -1}, true},
+		{"edge: zero total", []string{"a", "b"}, []int{0, 0}, true},
+		{"edge: empty", []string{}, []int{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := random.WeightedChoice(tt.items, tt.weights)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("WeightedChoice() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				idx := slices.Index(tt.items, got)
+				if idx < 0 || tt.weights[idx] == 0 {
+					t.Errorf("WeightedChoice() = %q, not a selectable item", got)
+				}
+			}
+		})
+	}
+	// Distribution check: selections should be roughly proportional to weights
+	items := []string{"a", "b", "c"}
+	weights := []int{1, 3, 6}
+	const runs = 10000
+	counts := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		got, err := random.WeightedChoice(items, weights)
+		if err != nil {
+			t.Fatalf("WeightedChoice() error = %v", err)
+		}
+		counts[got]++
+	}
+	for i, item := range items {
+		want := float64(weights[i]) / 10
+		observed := float64(counts[item]) / runs
+		if math.Abs(observed-want) > 0.03 {
+			t.Errorf("WeightedChoice() %q observed rate %f, want ~%f", item, observed, want)
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+	}{
+		{"edge: nil", nil},
+		{"edge: empty", []string{}},
+		{"edge: single", []string{"a"}},
+		{"happy: multiple", []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Clone(tt.items)
+			if err := random.Shuffle(got); err != nil {
+				t.Errorf("Shuffle() error = %v", err)
+			}
+			sorted := slices.Clone(got)
+			slices.Sort(sorted)
+			if !slices.Equal(sorted, tt.items) {
+				t.Errorf("Shuffle() = %v, not a permutation of %v", got, tt.items)
+			}
+		})
+	}
+	// Distribution check: each of the 6 permutations of 3 items should appear ~1000 times in 6000 runs
+	counts := make(map[string]int)
+	for i := 0; i < 6000; i++ {
+		items := []string{"a", "b", "c"}
+		if err := random.Shuffle(items); err != nil {
+			t.Fatalf("Shuffle() error = %v", err)
+		}
+		counts[strings.Join(items, "")]++
+	}
+	if len(counts) != 6 {
+		t.Errorf("Shuffle() produced %d distinct permutations, want 6", len(counts))
+	}
+	for perm, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Errorf("Shuffle() permutation %q appeared %d times in 6000 runs, want ~1000", perm, count)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name    string
+		items   []string
+		k       int
+		wantErr bool
+	}{
+		{"happy: subset", items, 3, false},
+		{"happy: all", items, 5, false},
+		{"happy: zero", items, 0, false},
+		{"edge: k > len", items, 6, true},
+		{"edge: k < 0", items, -1, true},
+		{"edge: empty items", nil, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := slices.Clone(tt.items)
+			got, err := random.Sample(tt.items, tt.k)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Sample() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(tt.items, original) {
+				t.Errorf("Sample() mutated input: %v, want %v", tt.items, original)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.k {
+				t.Errorf("Sample() len = %d, want %d", len(got), tt.k)
+			}
+			seen := make(map[string]bool)
+			for _, v := range got {
+				if !slices.Contains(tt.items, v) {
+					t.Errorf("Sample() = %q, not in items", v)
+				}
+				if seen[v] {
+					t.Errorf("Sample() duplicate element %q", v)
+				}
+				seen[v] = true
+			}
+		})
+	}
+	// Order check: sampling all items should not always preserve the input order
+	reordered := false
+	for i := 0; i < 100; i++ {
+		got, _ := random.Sample(items, len(items))
+		if !slices.Equal(got, items) {
+			reordered = true
+			break
+		}
+	}
+	if !reordered {
+		t.Errorf("Sample() preserved input order in 100 runs")
+	}
+}
+
+func TestFailureInjector(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		wantErr bool
+	}{
+		{"happy: zero", 0, false},
+		{"happy: one", 1, false},
+		{"happy: quarter", 0.25, false},
+		{"happy: half", 0.5, false},
+		{"edge: negative", -0.1, true},
+		{"edge: above one", 1.1, true},
+		{"edge: NaN", math.NaN(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inject, err := random.FailureInjector(tt.rate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FailureInjector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			// Distribution check: observed rate should approximate the configured rate
+			const runs = 10000
+			trueCount := 0
+			for i := 0; i < runs; i++ {
+				if inject() {
+					trueCount++
+				}
+			}
+			observed := float64(trueCount) / runs
+			if math.Abs(observed-tt.rate) > 0.03 {
+				t.Errorf("FailureInjector() observed rate %f, want ~%f", observed, tt.rate)
+			}
+		})
+	}
+}
+
+func TestFillStruct(t *testing.T) {
+	type inner struct {
+		Label string
+		Score float32
+	}
+	type record struct {
+		Name    string
+		Count   int
+		Size    uint16
+		Ratio   float64
+		Enabled bool
+		Tags    []string
+		Inner   inner
+		Ptr     *inner
+		Items   []inner
+		hidden  string
+	}
+
+	var r record
+	if err := random.FillStruct(&r); err != nil {
+		t.Fatalf("FillStruct() error = %v", err)
+	}
+	if r.Name == "" || r.Count == 0 || r.Size == 0 || r.Ratio == 0 {
+		t.Errorf("FillStruct() left scalar fields zero: %+v", r)
+	}
+	if len(r.Tags) == 0 || r.Tags[0] == "" {
+		t.Errorf("FillStruct() Tags = %v, want non-empty", r.Tags)
+	}
+	if r.Inner.Label == "" || r.Inner.Score == 0 {
+		t.Errorf("FillStruct() Inner = %+v, want non-zero", r.Inner)
+	}
+	if r.Ptr == nil || r.Ptr.Label == "" {
+		t.Errorf("FillStruct() Ptr = %v, want non-nil and filled", r.Ptr)
+	}
+	if len(r.Items) == 0 || r.Items[0].Label == "" {
+		t.Errorf("FillStruct() Items = %v, want non-empty and filled", r.Items)
+	}
+	if r.hidden != "" {
+		t.Errorf("FillStruct() filled unexported field: %q", r.hidden)
+	}
+
+	// Booleans may legitimately be false, so check both values appear across runs
+	seen := make(map[bool]bool)
+	for i := 0; i < 100; i++ {
+		var b struct{ Flag bool }
+		if err := random.FillStruct(&b); err != nil {
+			t.Fatalf("FillStruct() error = %v", err)
+		}
+		seen[b.Flag] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("FillStruct() bool field not randomized in 100 runs")
+	}
+
+	type node struct {
+		Value int
+		Next  *node
+	}
+	var n node
+	if err := random.FillStruct(&n); err != nil {
+		t.Errorf("FillStruct() self-referential error = %v", err)
+	}
+
+	errTests := []struct {
+		name string
+		v    any
+	}{
+		{"edge: nil", nil},
+		{"edge: non-pointer", record{}},
+		{"edge: pointer to non-struct", new(int)},
+		{"edge: nil pointer", (*record)(nil)},
+		{"edge: unsupported chan", &struct{ C chan int }{}},
+		{"edge: unsupported func", &struct{ F func() }{}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := random.FillStruct(tt.v); err == nil {
+				t.Errorf("FillStruct() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	// run draws a fixed sequence of values from a generator
+	run := func(g *random.Generator) []any {
+		n, _ := g.Int(1, 100)
+		n64, _ := g.Int64(math.MinInt64, math.MaxInt64)
+		f, _ := g.Float64(0, 1)
+		b, _ := g.Boolean()
+		h, _ := g.Hex(8)
+		c, _ := g.Choice([]string{"a", "b", "c"})
+		w, _ := g.WeightedChoice([]string{"a", "b"}, []int{1, 3})
+		items := []string{"a", "b", "c", "d", "e"}
+		g.Shuffle(items)
+		s, _ := g.Sample([]string{"a", "b", "c", "d", "e"}, 3)
+		return []any{g.String(10), g.Alphanumeric(6), n, n64, f, b, h, c, w, items, s}
+	}
+
+	first := run(random.NewGenerator(42))
+	second := run(random.NewGenerator(42))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Generator with same seed produced different output:\n%v\n%v", first, second)
+	}
+	other := run(random.NewGenerator(43))
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("Generator with different seeds produced identical output: %v", first)
+	}
+
+	g := random.NewGenerator(1)
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"edge: Int min>max", func() error { _, err := g.Int(10, 1); return err }},
+		{"edge: Int64 min>max", func() error { _, err := g.Int64(10, 1); return err }},
+		{"edge: Float64 NaN", func() error { _, err := g.Float64(math.NaN(), 1); return err }},
+		{"edge: Float64 inf", func() error { _, err := g.Float64(0, math.Inf(1)); return err }},
+		{"edge: Hex n<0", func() error { _, err := g.Hex(-1); return err }},
+		{"edge: Choice empty", func() error { _, err := g.Choice(nil); return err }},
+		{"edge: WeightedChoice zero total", func() error { _, err := g.WeightedChoice([]string{"a"}, []int{0}); return err }},
+		{"edge: Sample k > len", func() error { _, err := g.Sample([]string{"a"}, 2); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+
+	// Range checks
+	for i := 0; i < 100; i++ {
+		if n, _ := g.Int(-5, 5); n < -5 || n > 5 {
+			t.Errorf("Generator.Int() = %d, out of range [-5, 5]", n)
+		}
+		if s := g.String(5, "xyz"); !regexp.MustCompile(`^[xyz]{5}$`).MatchString(s) {
+			t.Errorf("Generator.String() = %q, does not match charset", s)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-// Package sanitize provides utilities for sanitizing strings, hostnames, file extensions, filenames, directory names, paths, and URLs.
+// Package sanitize provides utilities for sanitizing strings, hostnames, email addresses, phone numbers, URL slugs, SQL LIKE patterns and identifiers, file extensions, filenames, directory names, paths, and URLs.
 //
 // This package offers functions to clean and validate various types of input, ensuring they are safe for use in file systems, network operations, or other contexts.
 // It removes unsafe characters, normalizes spaces, and enforces platform-specific constraints (e.g., reserved filenames, path length limits).
@@ -7,19 +7,43 @@ package sanitize
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WhitespaceMode selects how StringWithMode handles whitespace.
+type WhitespaceMode int
+
+const (
+	// CollapseSpaces trims leading and trailing spaces and collapses runs of spaces into a single space.
+	// It is the behavior of String.
+	CollapseSpaces WhitespaceMode = iota
+	// PreserveSpaces keeps spaces as they are, including leading, trailing, and repeated ones, and converts
+	// tabs to single spaces instead of removing them.
+	PreserveSpaces
+	// StripAll removes all whitespace.
+	StripAll
 )
 
 // String sanitizes a string by removing control characters, replacing unsafe characters with spaces, and normalizing whitespace.
 //
 // The function removes all control characters, replaces characters like <, >, {, }, |, \, ^, and ~ with spaces,
-// trims leading/trailing spaces, and collapses multiple spaces into a single space. If the resulting string is empty,
-// an error is returned.
+// trims leading/trailing spaces, and collapses multiple spaces into a single space. If maxLen is given and positive,
+// the sanitized string is then truncated to at most maxLen bytes without splitting a character, and any trailing
+// space left by the cut is trimmed. If the resulting string is empty, an error is returned.
+// It is equivalent to StringWithMode with CollapseSpaces.
 //
 // Example:
 //
@@ -31,15 +55,51 @@ import (
 //
 // Parameters:
 //   - input: The string to sanitize.
+//   - maxLen: Optional maximum length of the result in bytes (no limit if omitted or not positive).
 //
 // Returns:
 //   - string: The sanitized string with control characters removed and spaces normalized.
 //   - error: An error if the sanitized string is empty.
-func String(input string) (string, error) {
-	// Remove control characters
+func String(input string, maxLen ...int) (string, error) {
+	return StringWithMode(input, CollapseSpaces, maxLen...)
+}
+
+// StringWithMode sanitizes a string like String, but lets the caller choose how whitespace is handled.
+//
+// In every mode, control characters are removed and characters like <, >, {, }, |, \, ^, and ~ are replaced with
+// spaces. CollapseSpaces then trims and collapses spaces as String does. PreserveSpaces keeps intentional formatting
+// such as indentation and column alignment: tabs become single spaces and all spaces are kept, though line breaks,
+// being control characters, are still removed. StripAll removes all whitespace, including the spaces that replaced
+// unsafe characters. If maxLen is given and positive, the result is truncated to at most maxLen bytes without
+// splitting a character (trimming a trailing space left by the cut with CollapseSpaces). If the result is empty or
+// contains only spaces, an error is returned.
+//
+// Example:
+//
+//	s, err := StringWithMode("Name:\tAlice  <admin>", PreserveSpaces)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%q\n", s) // Prints "Name: Alice   admin "
+//
+// Parameters:
+//   - input: The string to sanitize.
+//   - mode: The whitespace handling mode (CollapseSpaces, PreserveSpaces, or StripAll).
+//   - maxLen: Optional maximum length of the result in bytes (no limit if omitted or not positive).
+//
+// Returns:
+//   - string: The sanitized string.
+//   - error: An error if the mode is invalid or the sanitized string is empty.
+func StringWithMode(input string, mode WhitespaceMode, maxLen ...int) (string, error) {
+	if mode < CollapseSpaces || mode > StripAll {
+		return "", fmt.Errorf("invalid whitespace mode %d", mode)
+	}
+	// Remove control characters, keeping tabs as spaces when preserving spaces
 	var builder strings.Builder
 	for _, r := range input {
-		if !unicode.IsControl(r) {
+		if r == '\t' && mode == PreserveSpaces {
+			builder.WriteByte(' ')
+		} else if !unicode.IsControl(r) {
 			builder.WriteRune(r)
 		}
 	}
@@ -48,14 +108,197 @@ func String(input string) (string, error) {
 	unsafe := regexp.MustCompile(`[<>{}|\\^~]`)
 	result = unsafe.ReplaceAllString(result, " ")
 	// Normalize spaces
-	result = strings.TrimSpace(result)
-	result = regexp.MustCompile(`\s+`).ReplaceAllString(result, " ")
-	if result == "" {
+	switch mode {
+	case CollapseSpaces:
+		result = strings.TrimSpace(result)
+		result = regexp.MustCompile(`\s+`).ReplaceAllString(result, " ")
+	case StripAll:
+		result = strings.Join(strings.Fields(result), "")
+	}
+	// Truncate without splitting a character
+	if len(maxLen) > 0 && maxLen[0] > 0 && len(result) > maxLen[0] {
+		result = truncateUTF8(result, maxLen[0])
+		if mode == CollapseSpaces {
+			result = strings.TrimRight(result, " ")
+		}
+	}
+	if strings.TrimSpace(result) == "" {
 		return "", errors.New("sanitized string is empty")
 	}
 	return result, nil
 }
 
+// Normalize applies Unicode normalization to a string and then sanitizes it like String.
+//
+// String leaves combining characters and compatibility forms untouched, so visually identical strings such as
+// "é" (U+00E9) and "é" ("e" followed by U+0301) compare differently. Normalize first converts the input to the
+// given normalization form from golang.org/x/text/unicode/norm, then removes control characters, replaces unsafe
+// characters with spaces, and normalizes whitespace as String does. Use norm.NFC to unify composed and decomposed
+// characters, or norm.NFKC to also fold compatibility forms (e.g., "ﬁ" to "fi" or fullwidth "Ａ" to "A"), which
+// is recommended for deduplicating identifiers such as usernames.
+//
+// Example:
+//
+//	a, _ := Normalize("Jose\u0301", norm.NFC) // "e" + combining acute accent
+//	b, _ := Normalize("Jos\u00e9", norm.NFC)  // Precomposed "é"
+//	fmt.Println(a == b) // Prints true
+//
+// Parameters:
+//   - input: The string to normalize and sanitize.
+//   - form: The Unicode normalization form (norm.NFC, norm.NFD, norm.NFKC, or norm.NFKD).
+//
+// Returns:
+//   - string: The normalized and sanitized string.
+//   - error: An error if the form is invalid or the sanitized string is empty.
+func Normalize(input string, form norm.Form) (string, error) {
+	switch form {
+	case norm.NFC, norm.NFD, norm.NFKC, norm.NFKD:
+	default:
+		return "", fmt.Errorf("invalid normalization form %d", form)
+	}
+	return String(form.String(input))
+}
+
+// HTMLStrip converts HTML markup in user-submitted text to plain text.
+//
+// The function removes all tags and comments, drops the contents of <script> and <style> elements, decodes
+// character references (e.g., "&amp;" to "&"), and then removes control characters and collapses whitespace
+// like String does. Block-level elements such as <p>, <div>, <li>, and <br> separate the surrounding text with a
+// space, so words in adjacent paragraphs are not joined. This is not an HTML sanitizer: the result is plain text,
+// which may contain characters like "<" from decoded references and must still be escaped if rendered as HTML.
+// An error is returned if no text remains.
+//
+// Example:
+//
+//	s, err := HTMLStrip("<p>Hello <b>World</b> &amp; friends</p><script>alert(1)</script>")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(s) // Prints "Hello World & friends"
+//
+// Parameters:
+//   - input: The HTML text to strip.
+//
+// Returns:
+//   - string: The plain text with tags removed, references decoded, and whitespace normalized.
+//   - error: An error if the stripped text is empty.
+func HTMLStrip(input string) (string, error) {
+	var builder strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break // io.EOF, since the input is a string
+		}
+		switch tt {
+		case html.TextToken:
+			if skipDepth == 0 {
+				builder.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := atom.Lookup(name)
+			if tag == atom.Script || tag == atom.Style {
+				if tt == html.StartTagToken {
+					skipDepth++
+				} else if tt == html.EndTagToken && skipDepth > 0 {
+					skipDepth--
+				}
+			}
+			if htmlBlockElements[tag] {
+				builder.WriteByte(' ')
+			}
+		}
+	}
+	result := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, builder.String())
+	result = strings.Join(strings.Fields(result), " ")
+	if result == "" {
+		return "", errors.New("stripped text is empty")
+	}
+	return result, nil
+}
+
+// LikePattern escapes a string so that it is matched literally inside an SQL LIKE pattern.
+//
+// The LIKE wildcards '%' and '_' and the escape character '\' itself are each prefixed with a backslash, so user
+// input such as "50%_off" searches for exactly that text instead of acting as a pattern. Add any wildcards after
+// escaping (e.g., "%" + LikePattern(q) + "%" for a substring search). PostgreSQL and MySQL use '\' as the default
+// escape character; SQLite, SQL Server and Oracle need an explicit ESCAPE '\' clause. This is not a substitute for
+// parameterized queries: it only makes the pattern value behave literally, and the result must still be passed as
+// a query parameter. Unlike most functions in this package, it never fails, and an empty input yields an empty
+// pattern.
+//
+// Example:
+//
+//	pattern := "%" + LikePattern(`50%_off\sale`) + "%"
+//	fmt.Println(pattern) // Prints `%50\%\_off\\sale%`
+//	rows, err := db.Query(`SELECT name FROM products WHERE name LIKE ? ESCAPE '\'`, pattern)
+//
+// Parameters:
+//   - input: The literal text to search for.
+//
+// Returns:
+//   - string: The input with '%', '_' and '\' escaped for use in a LIKE pattern.
+func LikePattern(input string) string {
+	return likeReplacer.Replace(input)
+}
+
+// likeReplacer escapes the LIKE wildcards and the escape character with a backslash.
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Identifier sanitizes a string for use as an SQL identifier, such as a table or column name.
+//
+// Placeholders cannot be used for identifiers, so names built from configuration or user input must be restricted
+// before they are interpolated into a query. Surrounding whitespace is trimmed, inner whitespace and hyphens are
+// converted to underscores, and every character other than an ASCII letter, digit, or underscore is removed. The
+// result must start with a letter or underscore, must not exceed 63 characters (the PostgreSQL limit, which also
+// fits MySQL and SQL Server), and must not be an SQL reserved word (compared case-insensitively, e.g., "select"
+// or "Order"). Case is preserved; quote the identifier if the database should treat it case-sensitively.
+//
+// Example:
+//
+//	col, err := Identifier(" created-at ")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(col) // Prints "created_at"
+//
+// Parameters:
+//   - input: The identifier to sanitize.
+//
+// Returns:
+//   - string: The sanitized identifier containing only ASCII letters, digits, and underscores.
+//   - error: An error if the identifier is empty after cleaning, starts with a digit, is too long, or is a
+//     reserved word.
+func Identifier(input string) (string, error) {
+	fields := strings.FieldsFunc(strings.TrimSpace(input), func(r rune) bool { return unicode.IsSpace(r) || r == '-' })
+	result := strings.Map(func(r rune) rune {
+		if r == '_' || r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, strings.Join(fields, "_"))
+	if result == "" {
+		return "", errors.New("sanitized identifier is empty")
+	}
+	if result[0] >= '0' && result[0] <= '9' {
+		return "", fmt.Errorf("identifier %q must start with a letter or underscore", result)
+	}
+	if len(result) > 63 {
+		return "", errors.New("identifier exceeds maximum length of 63 characters")
+	}
+	if sqlReservedWords[strings.ToUpper(result)] {
+		return "", fmt.Errorf("identifier %q is an SQL reserved word", result)
+	}
+	return result, nil
+}
+
 // Hostname sanitizes a hostname or IP address to ensure it contains only valid characters.
 //
 // The function first applies String sanitization to remove control characters and normalize spaces,
@@ -89,10 +332,155 @@ func Hostname(input string) (string, error) {
 	return result, nil
 }
 
+// Email sanitizes and validates an email address, normalizing the domain to lowercase.
+//
+// The function trims surrounding whitespace, rejects control characters, and splits the address at its last "@".
+// The local part is kept as is, since it can be case-sensitive, and must be a dot-separated sequence of letters,
+// numbers, and the characters !#$%&'*+/=?^_`{|}~- (quoted local parts are not supported). The domain is lowercased
+// and must consist of dot-separated labels of letters, numbers, and hyphens that do not start or end with a hyphen.
+// The local part is limited to 64 bytes, each domain label to 63 bytes, and the whole address to 254 bytes.
+//
+// Example:
+//
+//	e, err := Email("  John.Doe+news@Example.COM ")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(e) // Prints "John.Doe+news@example.com"
+//
+// Parameters:
+//   - input: The email address to sanitize.
+//
+// Returns:
+//   - string: The sanitized email address with a lowercase domain.
+//   - error: An error if the address is empty, contains control characters, lacks an "@", or has an invalid
+//     local part or domain.
+func Email(input string) (string, error) {
+	result := strings.TrimSpace(input)
+	if result == "" {
+		return "", errors.New("sanitized email is empty")
+	}
+	if strings.IndexFunc(result, unicode.IsControl) >= 0 {
+		return "", errors.New("email contains control characters")
+	}
+	at := strings.LastIndex(result, "@")
+	if at < 0 {
+		return "", errors.New("email must contain @")
+	}
+	local, domain := result[:at], strings.ToLower(result[at+1:])
+	if local == "" {
+		return "", errors.New("email local part is empty")
+	}
+	if domain == "" {
+		return "", errors.New("email domain is empty")
+	}
+	if len(local) > 64 {
+		return "", errors.New("email local part too long")
+	}
+	localRegex := regexp.MustCompile("^[\\p{L}\\p{N}!#$%&'*+/=?^_`{|}~-]+(\\.[\\p{L}\\p{N}!#$%&'*+/=?^_`{|}~-]+)*$")
+	if !localRegex.MatchString(local) {
+		return "", errors.New("invalid email local part")
+	}
+	labelRegex := regexp.MustCompile(`^[\p{L}\p{N}]([\p{L}\p{N}-]*[\p{L}\p{N}])?$`)
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) > 63 || !labelRegex.MatchString(label) {
+			return "", errors.New("invalid email domain")
+		}
+	}
+	result = local + "@" + domain
+	if len(result) > 254 {
+		return "", errors.New("email too long")
+	}
+	return result, nil
+}
+
+// PhoneNumber normalizes a phone number to E.164 format (e.g., "+15551234567").
+//
+// The function drops a parenthesized trunk prefix "(0)", as in the common written form "+44 (0) 20 7946 0958",
+// strips spaces, dashes, dots, and parentheses, then interprets the remaining digits. Numbers starting with "+" or
+// the international prefix "00" (or "011" in the North American region) are taken to include a country calling
+// code. Other numbers are national numbers for defaultRegion, an ISO 3166-1 alpha-2 code such as "US" or
+// "GB": the region's trunk prefix (e.g., the leading "0" in "020 7946 0958") is removed and its calling code is
+// prepended. This is a lightweight implementation supporting a fixed set of common regions; it checks digit counts
+// (exactly 10 national digits for North American numbers, and 8 to 15 digits in total as E.164 allows) but does
+// not validate number ranges, so use a dedicated library where full validation matters.
+//
+// Example:
+//
+//	p, err := PhoneNumber("(555) 123-4567", "US")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(p) // Prints "+15551234567"
+//
+// Parameters:
+//   - input: The phone number to normalize, in international or national format.
+//   - defaultRegion: The region used for national numbers (may be empty if all numbers are international).
+//
+// Returns:
+//   - string: The phone number in E.164 format.
+//   - error: An error if the input is empty or contains invalid characters, the region is missing or unsupported
+//     for a national number, or the digit count is invalid.
+func PhoneNumber(input, defaultRegion string) (string, error) {
+	result := strings.TrimSpace(input)
+	if result == "" {
+		return "", errors.New("sanitized phone number is empty")
+	}
+	international := strings.HasPrefix(result, "+")
+	result = strings.TrimPrefix(result, "+")
+	// Drop an optional trunk prefix written as "(0)", which is not dialed after the country code
+	result = regexp.MustCompile(`\(\s*0\s*\)`).ReplaceAllString(result, "")
+	var digits strings.Builder
+	for _, r := range result {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("invalid character %q in phone number", r)
+		}
+	}
+	number := digits.String()
+	if number == "" {
+		return "", errors.New("phone number has no digits")
+	}
+
+	region, hasRegion := phoneRegions[strings.ToUpper(strings.TrimSpace(defaultRegion))]
+	if defaultRegion != "" && !hasRegion {
+		return "", fmt.Errorf("unsupported region %q", defaultRegion)
+	}
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case hasRegion && region.code == "1" && strings.HasPrefix(number, "011"):
+		number = number[3:]
+	case !hasRegion:
+		return "", errors.New("default region required for national phone numbers")
+	default:
+		if region.code == "1" && len(number) == 11 && strings.HasPrefix(number, "1") {
+			number = number[1:] // Long-distance prefix, e.g. "1 555 123 4567"
+		}
+		number = region.code + strings.TrimPrefix(number, region.trunk)
+	}
+
+	if number == "" || number[0] == '0' {
+		return "", errors.New("invalid country calling code")
+	}
+	if number[0] == '1' && len(number) != 11 {
+		return "", errors.New("North American phone numbers must have 10 digits after the country code")
+	}
+	if len(number) < 8 || len(number) > 15 {
+		return "", errors.New("phone number must have between 8 and 15 digits")
+	}
+	return "+" + number, nil
+}
+
 // Extension sanitizes a file extension to ensure it is safe and valid (e.g., ".txt", ".文档").
 //
 // The function converts the extension to lowercase, removes unsafe characters (keeping Unicode letters, numbers, and dots),
-// ensures it starts with a single dot, and removes multiple dots. An error is returned if the sanitized extension is empty or invalid.
+// ensures it starts with a single dot, and removes multiple dots. Lowercasing can be disabled with preserveCase for
+// case-sensitive systems where ".PDF" and ".pdf" differ. An error is returned if the sanitized extension is empty or invalid.
 //
 // Example:
 //
@@ -104,13 +492,17 @@ func Hostname(input string) (string, error) {
 //
 // Parameters:
 //   - ext: The file extension to sanitize (e.g., "txt" or ".txt").
+//   - preserveCase: Optional boolean indicating if the original case should be kept (defaults to false).
 //
 // Returns:
 //   - string: The sanitized file extension with a leading dot (e.g., ".txt").
 //   - error: An error if the sanitized extension is empty or invalid.
-func Extension(ext string) (string, error) {
+func Extension(ext string, preserveCase ...bool) (string, error) {
 	// Convert to lowercase and trim whitespace
-	ext = strings.ToLower(strings.TrimSpace(ext))
+	ext = strings.TrimSpace(ext)
+	if len(preserveCase) == 0 || !preserveCase[0] {
+		ext = strings.ToLower(ext)
+	}
 	// Remove unsafe characters, allow Unicode letters, numbers, and dot
 	safeExt := regexp.MustCompile(`[^\p{L}\p{N}.]`)
 	ext = safeExt.ReplaceAllString(ext, "")
@@ -130,11 +522,26 @@ func Extension(ext string) (string, error) {
 	return ext, nil
 }
 
+// FileNameOptions configures optional behavior of FileName.
+//
+// The zero value matches the default behavior of FileName.
+type FileNameOptions struct {
+	// PreserveExtensionCase keeps the original case of the extension (e.g., ".PDF") instead of lowercasing it.
+	PreserveExtensionCase bool
+	// ASCII transliterates accented and other Latin characters to their closest ASCII equivalents (e.g., "café.txt"
+	// to "cafe.txt", "Straße" to "Strasse") and drops characters that cannot be mapped, such as CJK characters, for
+	// storage backends that only accept ASCII filenames. An extension with no ASCII characters left is dropped.
+	ASCII bool
+}
+
 // FileName sanitizes a filename to ensure it is safe for file systems across Linux, macOS, and Windows.
 //
 // The function separates the base name and extension, sanitizes the base by removing unsafe characters and control characters,
-// checks for reserved filenames (e.g., "CON", "NUL"), and sanitizes the extension using Extension.
-// The sanitized filename is limited to 255 characters to comply with common filesystem limits.
+// rejects reserved filenames (e.g., "CON", "NUL"), and sanitizes the extension using Extension, which lowercases it
+// unless FileNameOptions.PreserveExtensionCase is set. Unicode letters and numbers are kept by default; set
+// FileNameOptions.ASCII to transliterate them to ASCII instead.
+// The sanitized filename is limited to 255 bytes to comply with common filesystem limits, truncating the base name on
+// a character boundary. The result is stable: sanitizing it again returns it unchanged (see IsStable).
 // An error is returned if the filename is empty, reserved, or invalid after sanitization.
 //
 // Example:
@@ -147,11 +554,16 @@ func Extension(ext string) (string, error) {
 //
 // Parameters:
 //   - filename: The filename to sanitize.
+//   - opts: Optional FileNameOptions. Defaults to the zero value if not provided.
 //
 // Returns:
 //   - string: The sanitized filename, including the extension if present.
 //   - error: An error if the filename is empty, reserved, or invalid after sanitization.
-func FileName(filename string) (string, error) {
+func FileName(filename string, opts ...FileNameOptions) (string, error) {
+	var options FileNameOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
 	// Handle special case for "."
 	if filename == "." {
 		return "", errors.New("sanitized filename is empty or invalid")
@@ -159,20 +571,19 @@ func FileName(filename string) (string, error) {
 	// Extract extension and base name
 	ext := filepath.Ext(filename)
 	base := strings.TrimSuffix(filename, ext)
+	// Transliterate to ASCII if requested, dropping an extension that has nothing left
+	if options.ASCII {
+		base = transliterate(base)
+		ext = transliterate(ext)
+		if strings.Trim(ext, ". ") == "" {
+			ext = ""
+		}
+	}
 	// Sanitize base name
 	base = strings.TrimSpace(base)
 	if base == "" {
 		return "", errors.New("filename base is empty")
 	}
-	// Check for reserved filenames
-	reservedNames := []string{
-		"CON", "PRN", "AUX", "NUL",
-		"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
-		"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
-	}
-	if slices.ContainsFunc(reservedNames, func(s string) bool { return strings.EqualFold(base, s) }) {
-		return "", errors.New("filename is a reserved name: " + base)
-	}
 	// Remove unsafe characters from base name, allow Unicode letters, numbers, underscores, and hyphens
 	unsafe := regexp.MustCompile(`[^\p{L}\p{N}_-]`)
 	base = unsafe.ReplaceAllString(base, "")
@@ -190,23 +601,30 @@ func FileName(filename string) (string, error) {
 	if base == "" {
 		return "", errors.New("sanitized filename base is empty")
 	}
+	// Check for reserved filenames after sanitization, so that e.g. "CON_" cannot sanitize to "CON"
+	if slices.ContainsFunc(windowsReservedNames, func(s string) bool { return strings.EqualFold(base, s) }) {
+		return "", errors.New("filename is a reserved name: " + base)
+	}
 	// Sanitize extension
 	var sanitizedExt string
 	var err error
 	if ext != "" {
-		sanitizedExt, err = Extension(ext)
+		sanitizedExt, err = Extension(ext, options.PreserveExtensionCase)
 		if err != nil {
 			return "", err
 		}
 	} else {
 		sanitizedExt = "" // No extension provided
 	}
+	// Ensure filename isn't too long (limit to 255 bytes, common filesystem limit) without splitting a character
+	if len(base)+len(sanitizedExt) > 255 {
+		if len(sanitizedExt) >= 255 {
+			return "", errors.New("sanitized extension is too long")
+		}
+		base = strings.TrimRight(truncateUTF8(base, 255-len(sanitizedExt)), "_")
+	}
 	// Combine base and extension
 	filename = base + sanitizedExt
-	// Ensure filename isn't too long (limit to 255 characters, common filesystem limit)
-	if len(filename) > 255 {
-		filename = filename[:255-len(sanitizedExt)] + sanitizedExt
-	}
 	// Return error if the result is empty or invalid
 	if filename == "" || filename == "." {
 		return "", errors.New("sanitized filename is empty or invalid")
@@ -217,8 +635,10 @@ func FileName(filename string) (string, error) {
 // DirName sanitizes a directory name to ensure it is safe for file systems across Linux, macOS, and Windows.
 //
 // The function removes unsafe characters, control characters, and leading/trailing slashes, ensures the name
-// does not start with a dot (to avoid hidden directories), and collapses multiple underscores.
-// The sanitized directory name is limited to 255 characters to comply with common filesystem limits.
+// does not start with a dot (to avoid hidden directories), and collapses multiple underscores. If allowHidden is
+// true, a leading dot is kept instead (multiple leading dots are collapsed to one), so names like ".config" survive.
+// The sanitized directory name is limited to 255 bytes to comply with common filesystem limits, truncating on a
+// character boundary. The result is stable: sanitizing it again returns it unchanged (see IsStable).
 // An error is returned if the directory name is empty or invalid after sanitization.
 //
 // Example:
@@ -231,11 +651,12 @@ func FileName(filename string) (string, error) {
 //
 // Parameters:
 //   - dirname: The directory name to sanitize.
+//   - allowHidden: Optional boolean indicating if a single leading dot should be kept (defaults to false).
 //
 // Returns:
 //   - string: The sanitized directory name.
 //   - error: An error if the directory name is empty or invalid after sanitization.
-func DirName(dirname string) (string, error) {
+func DirName(dirname string, allowHidden ...bool) (string, error) {
 	// Trim whitespace and remove leading/trailing slashes
 	dirname = strings.TrimSpace(dirname)
 	dirname = strings.Trim(dirname, "/\\")
@@ -243,9 +664,15 @@ func DirName(dirname string) (string, error) {
 	if dirname == "" {
 		return "", errors.New("directory name is empty")
 	}
-	// Ensure the name doesn't start with a dot (hidden directory)
+	// Keep a single leading dot if hidden directories are allowed, otherwise ensure the name doesn't start with one
+	prefix := ""
 	if strings.HasPrefix(dirname, ".") {
-		dirname = "dir_" + strings.TrimLeft(dirname, ".")
+		if len(allowHidden) > 0 && allowHidden[0] {
+			prefix = "."
+			dirname = strings.TrimLeft(dirname, ".")
+		} else {
+			dirname = "dir_" + strings.TrimLeft(dirname, ".")
+		}
 	}
 	// Remove unsafe characters, allow Unicode letters, numbers, underscores, and hyphens
 	unsafe := regexp.MustCompile(`[^\p{L}\p{N}_-]`)
@@ -257,18 +684,113 @@ func DirName(dirname string) (string, error) {
 		}
 		return r
 	}, dirname)
-	// Collapse multiple underscores and trim
+	// Collapse multiple underscores, limit to 255 bytes without splitting a character, and trim
 	dirname = regexp.MustCompile(`_+`).ReplaceAllString(dirname, "_")
+	dirname = truncateUTF8(dirname, 255-len(prefix))
 	dirname = strings.Trim(dirname, "_")
 	// Return error if the result is empty
 	if dirname == "" {
 		return "", errors.New("sanitized directory name is empty")
 	}
-	// Ensure directory name isn't too long (limit to 255 characters)
-	if len(dirname) > 255 {
-		dirname = dirname[:255]
+	return prefix + dirname, nil
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a multi-byte UTF-8 character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// windowsReservedNames are the device names that Windows reserves, regardless of case and extension.
+var windowsReservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// isWindowsReserved reports whether a path component refers to a reserved device name, which Windows matches
+// on the part before the first dot with trailing spaces ignored (e.g., "nul.txt" or "CON .log").
+func isWindowsReserved(name string) bool {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
 	}
-	return dirname, nil
+	name = strings.TrimRight(name, " ")
+	return slices.ContainsFunc(windowsReservedNames, func(s string) bool { return strings.EqualFold(name, s) })
+}
+
+// asciiReplacements maps characters that do not decompose into ASCII under NFKD to their closest ASCII equivalents.
+var asciiReplacements = map[rune]string{
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'þ': "th", 'Þ': "Th", 'ł': "l", 'Ł': "L",
+	'ı': "i", 'ħ': "h", 'Ħ': "H", 'ŧ': "t", 'Ŧ': "T", 'ŋ': "ng", 'Ŋ': "NG", 'ĸ': "q",
+	'‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"", '„': "\"", '–': "-", '—': "-",
+}
+
+// transliterate approximates s in ASCII: characters are decomposed with NFKD, combining marks are dropped,
+// asciiReplacements are applied, and other non-ASCII letters and numbers are dropped. Remaining non-ASCII
+// characters, such as symbols and non-ASCII spaces, are replaced with a space.
+func transliterate(s string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case r < utf8.RuneSelf:
+			builder.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+		case asciiReplacements[r] != "":
+			builder.WriteString(asciiReplacements[r])
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+		default:
+			builder.WriteByte(' ')
+		}
+	}
+	return builder.String()
+}
+
+// htmlBlockElements are the HTML elements whose tags separate the surrounding text in HTMLStrip.
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Br: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true,
+	atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true, atom.Nav: true, atom.Ol: true,
+	atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true, atom.Td: true, atom.Th: true,
+	atom.Title: true, atom.Tr: true, atom.Ul: true,
+}
+
+// phoneRegion holds the country calling code and national trunk prefix of a region for PhoneNumber.
+type phoneRegion struct {
+	code  string
+	trunk string
+}
+
+// phoneRegions maps the ISO 3166-1 alpha-2 codes supported by PhoneNumber to their calling code and trunk prefix.
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", ""}, "CA": {"1", ""}, "MX": {"52", ""}, "BR": {"55", "0"}, "AR": {"54", "0"},
+	"GB": {"44", "0"}, "IE": {"353", "0"}, "FR": {"33", "0"}, "DE": {"49", "0"}, "NL": {"31", "0"},
+	"BE": {"32", "0"}, "CH": {"41", "0"}, "AT": {"43", "0"}, "ES": {"34", ""}, "PT": {"351", ""},
+	"IT": {"39", ""}, "SE": {"46", "0"}, "NO": {"47", ""}, "DK": {"45", ""}, "FI": {"358", "0"},
+	"PL": {"48", ""}, "ZA": {"27", "0"}, "IN": {"91", "0"}, "CN": {"86", "0"}, "JP": {"81", "0"},
+	"SG": {"65", ""}, "HK": {"852", ""}, "AU": {"61", "0"}, "NZ": {"64", "0"},
+}
+
+// sqlReservedWords are the keywords reserved by standard SQL and the common databases, which Identifier rejects.
+var sqlReservedWords = map[string]bool{
+	"ADD": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true, "AS": true, "ASC": true, "BETWEEN": true,
+	"BY": true, "CASE": true, "CAST": true, "CHECK": true, "COLUMN": true, "CONSTRAINT": true, "CREATE": true,
+	"CROSS": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true,
+	"DATABASE": true, "DEFAULT": true, "DELETE": true, "DESC": true, "DISTINCT": true, "DROP": true, "ELSE": true,
+	"END": true, "EXCEPT": true, "EXISTS": true, "FALSE": true, "FETCH": true, "FOR": true, "FOREIGN": true,
+	"FROM": true, "FULL": true, "GRANT": true, "GROUP": true, "HAVING": true, "IN": true, "INDEX": true,
+	"INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true, "IS": true, "JOIN": true, "KEY": true,
+	"LEFT": true, "LIKE": true, "LIMIT": true, "NATURAL": true, "NOT": true, "NULL": true, "OFFSET": true,
+	"ON": true, "OR": true, "ORDER": true, "OUTER": true, "PRIMARY": true, "REFERENCES": true, "REVOKE": true,
+	"RIGHT": true, "ROW": true, "SELECT": true, "SESSION_USER": true, "SET": true, "SOME": true, "TABLE": true,
+	"THEN": true, "TO": true, "TRUE": true, "TRUNCATE": true, "UNION": true, "UNIQUE": true, "UPDATE": true,
+	"USER": true, "USING": true, "VALUES": true, "VIEW": true, "WHEN": true, "WHERE": true, "WITH": true,
 }
 
 // Path sanitizes a file path to ensure it is safe for file systems across Linux, macOS, and Windows.
@@ -276,7 +798,11 @@ func DirName(dirname string) (string, error) {
 // The function normalizes path separators, sanitizes each component (directories and the optional file),
 // and resolves relative components ('.' and '..') using filepath.Clean. If allowNav is true, leading ./ or ../
 // is preserved in the output. The function ensures the path is not empty, not root, and does not exceed 4096 characters.
-// A trailing separator is added for directory paths. An error is returned if the path is invalid or empty after sanitization.
+// A trailing separator is added for directory paths. Absolute paths are allowed by default; if allowAbsolute is false,
+// a path beginning with a separator is rejected, and if allowNav is also false, so is a path that still climbs above
+// its starting directory after '..' components are resolved (e.g., "a/../../x"). This gives a strict "relative, no
+// traversal" mode for paths that must stay inside a base directory. An error is returned if the path is invalid
+// or empty after sanitization.
 //
 // Example:
 //
@@ -289,11 +815,13 @@ func DirName(dirname string) (string, error) {
 // Parameters:
 //   - path: The file path to sanitize.
 //   - allowNav: If true, preserves leading ./ or ../ in the output; otherwise, resolves them fully.
+//   - allowAbsolute: Optional boolean indicating if absolute paths are accepted (defaults to true).
 //
 // Returns:
 //   - string: The sanitized file path with normalized separators and a trailing separator for directories.
-//   - error: An error if the path is empty, invalid, or exceeds the maximum length.
-func Path(path string, allowNav bool) (string, error) {
+//   - error: An error if the path is empty, invalid, absolute or escaping the base directory when not allowed,
+//     or exceeds the maximum length.
+func Path(path string, allowNav bool, allowAbsolute ...bool) (string, error) {
 	// Preserve leading ./ or ../ for relative paths
 	hasLeadingDotSlash := strings.HasPrefix(path, "./")
 	hasLeadingParentSlash := strings.HasPrefix(path, "../")
@@ -304,6 +832,9 @@ func Path(path string, allowNav bool) (string, error) {
 	}
 	slashedPath := filepath.ToSlash(path)
 	isAbs := strings.HasPrefix(slashedPath, "/")
+	if (isAbs || filepath.IsAbs(path)) && len(allowAbsolute) > 0 && !allowAbsolute[0] {
+		return "", errors.New("path must not be absolute")
+	}
 	// Split into components and sanitize each
 	components := strings.Split(slashedPath, "/")
 	var cleanComponents []string
@@ -342,6 +873,12 @@ func Path(path string, allowNav bool) (string, error) {
 	if finalPath == "" || finalPath == "." || finalPath == string(os.PathSeparator) {
 		return "", errors.New("sanitized path is empty")
 	}
+	// In strict mode, reject paths that climb above the base directory
+	if !allowNav && len(allowAbsolute) > 0 && !allowAbsolute[0] {
+		if slashedFinal := filepath.ToSlash(finalPath); slashedFinal == ".." || strings.HasPrefix(slashedFinal, "../") {
+			return "", errors.New("path must not escape the base directory")
+		}
+	}
 	// Reattach leading ./ or ../ if applicable
 	if allowNav {
 		slashedFinal := filepath.ToSlash(finalPath)
@@ -362,6 +899,161 @@ func Path(path string, allowNav bool) (string, error) {
 	return finalPath, nil
 }
 
+// WindowsPath sanitizes a file path for use on Windows, independently of the operating system it runs on.
+//
+// Path uses the separator of the operating system it runs on, so a tool running on Linux cannot use it to generate
+// paths for a Windows client. WindowsPath accepts both "/" and "\" as separators and always produces backslash
+// separators. A leading drive letter (e.g., "c:") is kept and uppercased; drive-relative paths such as "C:dir" are
+// treated as "C:\dir", and UNC prefixes are not supported. Trailing dots and spaces, which Windows silently drops,
+// are stripped from each component before it is sanitized with FileName or DirName, and components that are
+// reserved device names (e.g., "CON", "nul.txt", "COM1") are rejected. Relative components are resolved as by Path,
+// and if allowNav is true, a leading .\ or ..\ is preserved. A trailing separator is added for directory paths,
+// and the result must not exceed 259 UTF-16 code units (the classic MAX_PATH limit, excluding the terminating NUL).
+//
+// Example:
+//
+//	p, err := WindowsPath("c:/Users/me/report .txt. ", false)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(p) // Prints `C:\Users\me\report.txt`
+//
+// Parameters:
+//   - path: The file path to sanitize, using "/" or "\" as separators.
+//   - allowNav: If true, preserves leading .\ or ..\ in the output; otherwise, resolves them fully.
+//
+// Returns:
+//   - string: The sanitized Windows path with backslash separators and a trailing separator for directories.
+//   - error: An error if the path is empty, contains a reserved device name, or exceeds the maximum length.
+func WindowsPath(path string, allowNav bool) (string, error) {
+	slashedPath := strings.ReplaceAll(strings.TrimSpace(path), `\`, "/")
+	if slashedPath == "" {
+		return "", errors.New("path is empty")
+	}
+	// Extract the drive letter, if any
+	drive := ""
+	if len(slashedPath) >= 2 && slashedPath[1] == ':' && unicode.IsLetter(rune(slashedPath[0])) && slashedPath[0] < utf8.RuneSelf {
+		drive = strings.ToUpper(slashedPath[:1]) + ":"
+		slashedPath = slashedPath[2:]
+	}
+	hasLeadingDotSlash := drive == "" && strings.HasPrefix(slashedPath, "./")
+	hasLeadingParentSlash := drive == "" && strings.HasPrefix(slashedPath, "../")
+	isAbs := drive != "" || strings.HasPrefix(slashedPath, "/")
+	// Split into components and sanitize each
+	components := strings.Split(slashedPath, "/")
+	var cleanComponents []string
+	for i, comp := range components {
+		if comp != "." && comp != ".." {
+			comp = strings.TrimRight(comp, ". ")
+		}
+		if comp == "" {
+			continue // Skip empties
+		}
+		if comp == "." || comp == ".." {
+			cleanComponents = append(cleanComponents, comp) // Preserve nav
+			continue
+		}
+		if isWindowsReserved(comp) {
+			return "", fmt.Errorf("path component %q is a reserved Windows device name", comp)
+		}
+		var sanitizedComp string
+		var err error
+		if i == len(components)-1 && HasFileExtension(comp) {
+			sanitizedComp, err = FileName(comp)
+		} else {
+			sanitizedComp, err = DirName(comp)
+		}
+		if err != nil {
+			continue
+		}
+		if isWindowsReserved(sanitizedComp) {
+			return "", fmt.Errorf("path component %q is a reserved Windows device name", sanitizedComp)
+		}
+		cleanComponents = append(cleanComponents, sanitizedComp)
+	}
+	// Build and clean the path, which cannot climb above the root of an absolute path
+	var cleanPath string
+	if isAbs {
+		cleanPath = strings.TrimPrefix(pathpkg.Join(append([]string{"/"}, cleanComponents...)...), "/")
+	} else {
+		cleanPath = pathpkg.Join(cleanComponents...)
+	}
+	if cleanPath == "" || cleanPath == "." {
+		return "", errors.New("sanitized path is empty")
+	}
+	// Reattach leading ./ or ../ if applicable
+	if allowNav {
+		if hasLeadingDotSlash && !strings.HasPrefix(cleanPath, "./") {
+			cleanPath = "./" + cleanPath
+		} else if hasLeadingParentSlash && !strings.HasPrefix(cleanPath, "../") {
+			cleanPath = "../" + cleanPath
+		}
+	}
+	finalPath := strings.ReplaceAll(cleanPath, "/", `\`)
+	if isAbs {
+		finalPath = drive + `\` + finalPath
+	}
+	// Add trailing separator for directories
+	if !HasFileExtension(pathpkg.Base(cleanPath)) {
+		finalPath += `\`
+	}
+	// Ensure path isn't too long, counting UTF-16 code units as Windows does
+	if len(utf16.Encode([]rune(finalPath))) > 259 {
+		return "", errors.New("sanitized path exceeds maximum length")
+	}
+	return finalPath, nil
+}
+
+// ArchivePath validates and canonicalizes a path for use as a zip or tar archive entry name.
+//
+// The function converts backslashes to forward slashes, strips a leading Windows drive letter (e.g., "C:") and
+// leading slashes, and removes empty and '.' components. Any '..' component is rejected rather than resolved, so
+// the resulting entry can never escape the extraction directory ("zip slip"). An error is returned if the path
+// is empty, contains control characters, contains a '..' component, or is empty after cleaning.
+//
+// Example:
+//
+//	p, err := ArchivePath(`\docs\.\reports\q1.pdf`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(p) // Prints "docs/reports/q1.pdf"
+//
+// Parameters:
+//   - path: The path to convert into an archive entry name.
+//
+// Returns:
+//   - string: The clean, forward-slash, relative entry name.
+//   - error: An error if the path is empty, contains control characters or '..' components, or is empty after cleaning.
+func ArchivePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("path is empty")
+	}
+	if strings.ContainsFunc(path, unicode.IsControl) {
+		return "", errors.New("path contains control characters")
+	}
+	path = strings.ReplaceAll(path, "\\", "/")
+	// Strip a Windows drive letter such as "C:"
+	if len(path) >= 2 && path[1] == ':' && unicode.IsLetter(rune(path[0])) {
+		path = path[2:]
+	}
+	var components []string
+	for _, comp := range strings.Split(path, "/") {
+		switch comp {
+		case "", ".":
+			continue
+		case "..":
+			return "", errors.New("path must not contain '..' components")
+		}
+		components = append(components, comp)
+	}
+	if len(components) == 0 {
+		return "", errors.New("sanitized path is empty")
+	}
+	return strings.Join(components, "/"), nil
+}
+
 // Url sanitizes a URL string by removing control characters, trimming whitespace, and validating its format.
 //
 // The function ensures the URL contains valid characters and optionally requires a protocol (http:// or https://).
@@ -411,6 +1103,137 @@ func Url(input string, requireProtocol ...bool) (string, error) {
 	return result, nil
 }
 
+// Slug converts a string, such as a title, into a lowercase, URL-friendly identifier.
+//
+// The function transliterates accented and other Latin characters to ASCII (e.g., "é" to "e", "ß" to "ss"),
+// lowercases the result, replaces each run of characters other than a-z and 0-9 with a single hyphen, and trims
+// leading and trailing hyphens. Letters with no ASCII equivalent, such as CJK characters, are dropped.
+// If maxLength is given and positive, the slug is shortened to at most maxLength bytes by cutting at the last
+// hyphen that fits, so words are not split; only a single word longer than maxLength is cut mid-word.
+// An error is returned if the resulting slug is empty.
+//
+// Example:
+//
+//	s, err := Slug("Héllo, World!")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(s) // Prints "hello-world"
+//
+// Parameters:
+//   - input: The string to convert to a slug.
+//   - maxLength: Optional maximum length of the slug in bytes (no limit if omitted or not positive).
+//
+// Returns:
+//   - string: The slug, containing only a-z, 0-9, and single hyphens between words.
+//   - error: An error if the slug is empty.
+func Slug(input string, maxLength ...int) (string, error) {
+	var builder strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(transliterate(input)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingHyphen && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			pendingHyphen = false
+			builder.WriteRune(r)
+		} else {
+			pendingHyphen = true
+		}
+	}
+	slug := builder.String()
+	if len(maxLength) > 0 && maxLength[0] > 0 && len(slug) > maxLength[0] {
+		if cut := strings.LastIndexByte(slug[:maxLength[0]+1], '-'); cut > 0 {
+			slug = slug[:cut]
+		} else {
+			slug = slug[:maxLength[0]]
+		}
+	}
+	if slug == "" {
+		return "", errors.New("sanitized slug is empty")
+	}
+	return slug, nil
+}
+
+// List splits a delimited string into a slice of sanitized, unique elements.
+//
+// The function splits the input on sep, sanitizes each element using String (which trims and normalizes whitespace),
+// drops elements that are empty after sanitization, and removes duplicates while preserving first-seen order.
+// An error is returned if sep is empty or if no valid elements remain.
+//
+// Example:
+//
+//	tags, err := List("go, web ,,go", ",")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(tags) // Prints [go web]
+//
+// Parameters:
+//   - input: The delimited string to split and sanitize.
+//   - sep: The separator to split on (e.g., ",").
+//
+// Returns:
+//   - []string: The sanitized, deduplicated elements in first-seen order.
+//   - error: An error if sep is empty or no valid elements remain after sanitization.
+func List(input string, sep string) ([]string, error) {
+	if sep == "" {
+		return nil, errors.New("separator cannot be empty")
+	}
+	var result []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(input, sep) {
+		item, err := String(part)
+		if err != nil {
+			continue // Drop empty elements
+		}
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("sanitized list is empty")
+	}
+	return result, nil
+}
+
+// IsStable reports whether a sanitizer is idempotent for the given input, i.e., applying it to its own output
+// returns that output unchanged.
+//
+// The function applies fn to input, then applies fn again to the result, and reports whether both results match.
+// A stable sanitizer can safely be re-applied to already sanitized values, which matters when sanitized strings
+// are cached, compared, or sanitized again further down a pipeline. If the first application fails, its error is
+// returned; if only the second fails, the sanitizer is not stable and false is returned with the error.
+//
+// Example:
+//
+//	stable, err := IsStable(FileName, "my<file>.TXT")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(stable) // Prints true
+//
+// Parameters:
+//   - fn: The sanitizer to check, such as FileName or DirName.
+//   - input: The input to sanitize.
+//
+// Returns:
+//   - bool: True if sanitizing the sanitized output yields the same output.
+//   - error: An error if fn fails on the input or on its own output.
+func IsStable(fn func(string) (string, error), input string) (bool, error) {
+	first, err := fn(input)
+	if err != nil {
+		return false, err
+	}
+	second, err := fn(first)
+	if err != nil {
+		return false, fmt.Errorf("sanitizing output %q failed: %w", first, err)
+	}
+	return first == second, nil
+}
+
 // HasFileExtension checks if the provided string has a valid file extension.
 //
 // A valid extension is a non-empty suffix starting with a dot (e.g., ".txt").
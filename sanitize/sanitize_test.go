@@ -1,11 +1,14 @@
 package sanitize_test
 
 import (
+	"errors"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/devify-me/devify-utils/sanitize"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestString(t *testing.T) {
@@ -39,6 +42,205 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestStringMaxLen(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		maxLen  []int
+		want    string
+		wantErr bool
+	}{
+		{"happy: omitted is unlimited", strings.Repeat("a", 10000), nil, strings.Repeat("a", 10000), false},
+		{"happy: shorter than limit", "hello", []int{10}, "hello", false},
+		{"happy: exact limit", "hello", []int{5}, "hello", false},
+		{"happy: truncated", "hello world", []int{8}, "hello wo", false},
+		{"happy: applied after normalization", "  hello \t\t  world  ", []int{11}, "hello world", false},
+		{"happy: trailing space trimmed", "hello world", []int{6}, "hello", false},
+		{"happy: rune boundary", "héllo", []int{2}, "h", false},
+		{"happy: multibyte kept whole", "世界和平", []int{7}, "世界", false},
+		{"happy: zero is unlimited", "hello", []int{0}, "hello", false},
+		{"edge: nothing fits", "世界", []int{2}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.String(tt.input, tt.maxLen...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("String() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringWithMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mode    sanitize.WhitespaceMode
+		maxLen  []int
+		want    string
+		wantErr bool
+	}{
+		{"happy: collapse matches String", "  Hello\t<World>  ! ", sanitize.CollapseSpaces, nil, "Hello World !", false},
+		{"happy: preserve keeps spaces", "  a  b   c  ", sanitize.PreserveSpaces, nil, "  a  b   c  ", false},
+		{"happy: preserve tabs as spaces", "Name:\tAlice  <admin>", sanitize.PreserveSpaces, nil, "Name: Alice   admin ", false},
+		{"happy: preserve strips control chars", "a\x00b\nc", sanitize.PreserveSpaces, nil, "abc", false},
+		{"happy: preserve truncation keeps trailing space", "ab  cd", sanitize.PreserveSpaces, []int{3}, "ab ", false},
+		{"happy: strip all", " a b\t<c>  d ", sanitize.StripAll, nil, "abcd", false},
+		{"happy: strip all unicode spaces", "a\u00a0b\u2003c", sanitize.StripAll, nil, "abc", false},
+		{"happy: strip all truncated", "a b c d e", sanitize.StripAll, []int{3}, "abc", false},
+		{"edge: preserve only spaces", "   \t ", sanitize.PreserveSpaces, nil, "", true},
+		{"edge: strip all only unsafe", "<> ~", sanitize.StripAll, nil, "", true},
+		{"edge: invalid mode", "abc", sanitize.WhitespaceMode(7), nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.StringWithMode(tt.input, tt.mode, tt.maxLen...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StringWithMode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("StringWithMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		form    norm.Form
+		want    string
+		wantErr bool
+	}{
+		{"happy: NFC composes", "Jose\u0301", norm.NFC, "Jos\u00e9", false},
+		{"happy: NFC keeps composed", "Jos\u00e9", norm.NFC, "Jos\u00e9", false},
+		{"happy: NFD decomposes", "Jos\u00e9", norm.NFD, "Jose\u0301", false},
+		{"happy: NFC keeps compatibility forms", "ﬁle", norm.NFC, "ﬁle", false},
+		{"happy: NFKC folds compatibility forms", "ﬁle Ａ", norm.NFKC, "file A", false},
+		{"happy: sanitizes like String", "  a\u0301\t<b>  ", norm.NFC, "\u00e1 b", false},
+		{"happy: NFKC output is sanitized", "a＜b＞", norm.NFKC, "a b", false},
+		{"edge: empty", "", norm.NFC, "", true},
+		{"edge: only control characters", "\x00\x01", norm.NFKC, "", true},
+		{"edge: invalid form", "abc", norm.Form(42), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Normalize(tt.input, tt.form)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLStrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"happy: plain text", "Hello World", "Hello World", false},
+		{"happy: inline tags", "<p>Hello <b>World</b></p>", "Hello World", false},
+		{"happy: inline tag inside word", "Hel<i>lo</i>", "Hello", false},
+		{"happy: entities decoded", "Tom &amp; Jerry &lt;3 &quot;cheese&quot; &#169; &eacute;", `Tom & Jerry <3 "cheese" © é`, false},
+		{"happy: block elements separate words", "<p>One</p><p>Two</p><ul><li>a</li><li>b</li></ul>line<br>break", "One Two a b line break", false},
+		{"happy: script and style dropped", "<style>p{color:red}</style>Hi<script>alert('x<y')</script>!", "Hi!", false},
+		{"happy: comments dropped", "a<!-- secret -->b", "ab", false},
+		{"happy: attributes with angle brackets", `<a href="x" title="a > b">link</a>`, "link", false},
+		{"happy: whitespace collapsed", "  <div>\n\tSpaced \r\n  out  </div> ", "Spaced out", false},
+		{"happy: control characters removed", "a\x00b\x07c", "abc", false},
+		{"happy: unclosed tag", "text <b>bold", "text bold", false},
+		{"edge: empty", "", "", true},
+		{"edge: only markup", "<div><br/><img src='x.png'></div>", "", true},
+		{"edge: only script", "<script>alert(1)</script>", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.HTMLStrip(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTMLStrip() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HTMLStrip() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"happy: plain text", "hello world", "hello world"},
+		{"happy: percent", "50% off", `50\% off`},
+		{"happy: underscore", "snake_case", `snake\_case`},
+		{"happy: backslash", `C:\temp`, `C:\\temp`},
+		{"happy: unicode", "文件_名%", `文件\_名\%`},
+		{"edge: empty", "", ""},
+		{"edge: all specials", `%_\`, `\%\_\\`},
+		{"edge: already escaped", `\%`, `\\\%`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize.LikePattern(tt.input); got != tt.want {
+				t.Errorf("LikePattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"happy: simple", "users", "users", false},
+		{"happy: case preserved", "UserAccounts", "UserAccounts", false},
+		{"happy: hyphen and spaces", " created-at time ", "created_at_time", false},
+		{"happy: leading underscore", "_internal", "_internal", false},
+		{"happy: digits", "table2", "table2", false},
+		{"happy: reserved word as part", "order_id", "order_id", false},
+		{"edge: injection removed", "users; DROP TABLE users--", "users_DROP_TABLE_users", false},
+		{"edge: quotes removed", `"name"`, "name", false},
+		{"edge: unicode removed", "naïve", "nave", false},
+		{"edge: empty", "", "", true},
+		{"edge: only invalid", "$#@!", "", true},
+		{"edge: starts with digit", "1table", "", true},
+		{"edge: reserved word", "select", "", true},
+		{"edge: reserved word mixed case", "Order", "", true},
+		{"edge: too long", strings.Repeat("a", 64), "", true},
+		{"edge: max length", strings.Repeat("a", 63), strings.Repeat("a", 63), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Identifier(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Identifier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Identifier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHostname(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -69,6 +271,101 @@ func TestHostname(t *testing.T) {
 	}
 }
 
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"happy: simple", "alice@example.com", "alice@example.com", false},
+		{"happy: domain lowercased, local kept", "  John.Doe+news@Example.COM ", "John.Doe+news@example.com", false},
+		{"happy: special local chars", "o'brien_{x}=1@mail.example.org", "o'brien_{x}=1@mail.example.org", false},
+		{"happy: hyphenated domain", "a@sub-domain.example.co.uk", "a@sub-domain.example.co.uk", false},
+		{"happy: single label domain", "root@localhost", "root@localhost", false},
+		{"happy: unicode", "José@Exämple.com", "José@exämple.com", false},
+		{"edge: empty", "   ", "", true},
+		{"edge: missing @", "alice.example.com", "", true},
+		{"edge: empty local", "@example.com", "", true},
+		{"edge: empty domain", "alice@", "", true},
+		{"edge: two @", "a@b@example.com", "", true},
+		{"edge: control char", "alice\x00@example.com", "", true},
+		{"edge: newline injection", "alice@example.com\nBcc: x@y.com", "", true},
+		{"edge: space in local", "al ice@example.com", "", true},
+		{"edge: leading dot", ".alice@example.com", "", true},
+		{"edge: consecutive dots", "al..ice@example.com", "", true},
+		{"edge: invalid domain char", "alice@exa_mple.com", "", true},
+		{"edge: domain starts with hyphen", "alice@-example.com", "", true},
+		{"edge: empty domain label", "alice@example..com", "", true},
+		{"edge: trailing dot domain", "alice@example.com.", "", true},
+		{"edge: quoted local", `"alice"@example.com`, "", true},
+		{"edge: local too long", strings.Repeat("a", 65) + "@example.com", "", true},
+		{"edge: label too long", "a@" + strings.Repeat("b", 64) + ".com", "", true},
+		{"edge: address too long", "a@" + strings.Repeat(strings.Repeat("b", 60)+".", 5) + "com", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Email(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Email() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Email() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhoneNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		region  string
+		want    string
+		wantErr bool
+	}{
+		{"happy: US national", "(555) 123-4567", "US", "+15551234567", false},
+		{"happy: US with long-distance 1", "1-555-123-4567", "us", "+15551234567", false},
+		{"happy: US dotted", "555.123.4567", "US", "+15551234567", false},
+		{"happy: international plus", "+1 555 123 4567", "", "+15551234567", false},
+		{"happy: international ignores region", "+44 20 7946 0958", "US", "+442079460958", false},
+		{"happy: 00 prefix", "0044 20 7946 0958", "DE", "+442079460958", false},
+		{"happy: 011 prefix from US", "011 44 20 7946 0958", "US", "+442079460958", false},
+		{"happy: GB trunk zero removed", "020 7946 0958", "GB", "+442079460958", false},
+		{"happy: international with (0) trunk", "+44 (0) 20 7946 0958", "", "+442079460958", false},
+		{"happy: 00 prefix with (0) trunk", "0044 (0)20 7946 0958", "", "+442079460958", false},
+		{"happy: national with (0) trunk", "(0) 20 7946 0958", "GB", "+442079460958", false},
+		{"happy: DE national", "030 123456", "DE", "+4930123456", false},
+		{"happy: IT keeps leading zero", "06 1234 5678", "IT", "+390612345678", false},
+		{"happy: trailing whitespace", "  +33 1 23 45 67 89 ", "", "+33123456789", false},
+		{"edge: empty", "  ", "US", "", true},
+		{"edge: letters", "555-CALL-NOW", "US", "", true},
+		{"edge: extension", "+1 555 123 4567 x12", "", "", true},
+		{"edge: no digits", "()-", "US", "", true},
+		{"edge: national without region", "555 123 4567", "", "", true},
+		{"edge: unsupported region", "555 123 4567", "XX", "", true},
+		{"edge: US too short", "555-1234", "US", "", true},
+		{"edge: US too long", "+1 555 123 45678", "", "", true},
+		{"edge: too short", "+44 123", "", "", true},
+		{"edge: too long", "+44 1234 5678 9012 34", "", "", true},
+		{"edge: zero country code", "+0 123 456 789", "", "", true},
+		{"edge: plus in middle", "555+1234567", "US", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.PhoneNumber(tt.input, tt.region)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PhoneNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PhoneNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtension(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -118,6 +415,10 @@ func TestFileName(t *testing.T) {
 		{"edge: reserved like CON", "CON.txt", "", true},
 		{"edge: max length", strings.Repeat("a", 300) + ".txt", strings.Repeat("a", 255-len(".txt")) + ".txt", false},
 		{"edge: invalid base", "<>", "", true},
+		{"edge: reserved after sanitizing", "CON_.txt", "", true},
+		{"edge: max length multibyte", strings.Repeat("é", 200) + ".txt", strings.Repeat("é", 125) + ".txt", false},
+		{"edge: max length trailing underscore", strings.Repeat("a", 250) + "_b.txt", strings.Repeat("a", 250) + ".txt", false},
+		{"edge: extension too long", "a." + strings.Repeat("x", 300), "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -133,6 +434,76 @@ func TestFileName(t *testing.T) {
 	}
 }
 
+func TestExtensionPreserveCase(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		preserveCase []bool
+		want         string
+	}{
+		{"happy: default lowercases", ".PDF", nil, ".pdf"},
+		{"happy: explicit false lowercases", ".PDF", []bool{false}, ".pdf"},
+		{"happy: preserve upper", ".PDF", []bool{true}, ".PDF"},
+		{"happy: preserve mixed", "TaR!", []bool{true}, ".TaR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Extension(tt.input, tt.preserveCase...)
+			if err != nil {
+				t.Errorf("Extension() unexpected error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Extension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileNameOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []sanitize.FileNameOptions
+		want  string
+	}{
+		{"happy: default lowercases", "Report.PDF", nil, "Report.pdf"},
+		{"happy: zero options lowercase", "Report.PDF", []sanitize.FileNameOptions{{}}, "Report.pdf"},
+		{"happy: preserve extension case", "Report.PDF", []sanitize.FileNameOptions{{PreserveExtensionCase: true}}, "Report.PDF"},
+		{"happy: preserve with unsafe chars", "Re<port>.Md", []sanitize.FileNameOptions{{PreserveExtensionCase: true}}, "Report.Md"},
+		{"happy: default keeps unicode", "café.txt", nil, "café.txt"},
+		{"happy: ascii strips accents", "café.txt", []sanitize.FileNameOptions{{ASCII: true}}, "cafe.txt"},
+		{"happy: ascii decomposed accents", "Jose\u0301 Müller.PDF", []sanitize.FileNameOptions{{ASCII: true}}, "JoseMuller.pdf"},
+		{"happy: ascii transliterates special letters", "Straße_Ærø.md", []sanitize.FileNameOptions{{ASCII: true}}, "Strasse_AEro.md"},
+		{"happy: ascii drops unmappable", "报告report2024.txt", []sanitize.FileNameOptions{{ASCII: true}}, "report2024.txt"},
+		{"happy: ascii drops unmappable extension", "report.文档", []sanitize.FileNameOptions{{ASCII: true}}, "report"},
+		{"happy: ascii accented extension", "notes.Résumé", []sanitize.FileNameOptions{{ASCII: true, PreserveExtensionCase: true}}, "notes.Resume"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.FileName(tt.input, tt.opts...)
+			if err != nil {
+				t.Errorf("FileName() unexpected error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FileName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("edge: ascii with nothing mappable", func(t *testing.T) {
+		if _, err := sanitize.FileName("报告.txt", sanitize.FileNameOptions{ASCII: true}); err == nil {
+			t.Error("FileName() expected error for a base with no ASCII equivalent")
+		}
+	})
+	t.Run("happy: ascii is stable", func(t *testing.T) {
+		ascii := func(s string) (string, error) { return sanitize.FileName(s, sanitize.FileNameOptions{ASCII: true}) }
+		if stable, err := sanitize.IsStable(ascii, "Ça va — très bien ½.TXT"); err != nil || !stable {
+			t.Errorf("IsStable() = %v, %v, want true", stable, err)
+		}
+	})
+}
+
 func TestDirName(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -151,6 +522,8 @@ func TestDirName(t *testing.T) {
 		{"edge: control chars", "dir\x00", "dir", false},
 		{"edge: max length", strings.Repeat("a", 300), strings.Repeat("a", 255), false},
 		{"edge: only unsafe", "<>", "", true},
+		{"edge: max length multibyte", strings.Repeat("é", 200), strings.Repeat("é", 127), false},
+		{"edge: max length trailing underscore", strings.Repeat("a", 254) + "_b", strings.Repeat("a", 254), false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -166,6 +539,37 @@ func TestDirName(t *testing.T) {
 	}
 }
 
+func TestDirNameAllowHidden(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		allowHidden []bool
+		want        string
+		wantErr     bool
+	}{
+		{"happy: default rewrites dot", ".config", nil, "dir_config", false},
+		{"happy: explicit false rewrites dot", ".config", []bool{false}, "dir_config", false},
+		{"happy: keeps dot", ".config", []bool{true}, ".config", false},
+		{"happy: not hidden", "config", []bool{true}, "config", false},
+		{"edge: multiple dots", "...git", []bool{true}, ".git", false},
+		{"edge: underscore after dot", "._cache_", []bool{true}, ".cache", false},
+		{"edge: only dots", "..", []bool{true}, "", true},
+		{"edge: max length", "." + strings.Repeat("a", 300), []bool{true}, "." + strings.Repeat("a", 254), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.DirName(tt.input, tt.allowHidden...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DirName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DirName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -210,6 +614,119 @@ func TestPath(t *testing.T) {
 	}
 }
 
+func TestPathAllowAbsolute(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		allowNav      bool
+		allowAbsolute []bool
+		want          string
+		wantErr       bool
+	}{
+		{"happy: default allows absolute", "/etc/app/config.yaml", false, nil, "/etc/app/config.yaml", false},
+		{"happy: explicit true allows absolute", "/etc/app/", false, []bool{true}, "/etc/app/", false},
+		{"happy: relative allowed", "uploads/file.txt", false, []bool{false}, "uploads/file.txt", false},
+		{"happy: strict resolves nav", "./a/../b/file.txt", false, []bool{false}, "b/file.txt", false},
+		{"edge: absolute rejected", "/etc/passwd.txt", false, []bool{false}, "", true},
+		{"edge: absolute dir rejected", "/uploads", true, []bool{false}, "", true},
+		{"edge: double slash rejected", "//server/share", false, []bool{false}, "", true},
+		{"edge: leading space absolute rejected", "  /tmp/file.txt", false, []bool{false}, "", true},
+		{"edge: leading traversal rejected", "../../etc/passwd.txt", false, []bool{false}, "", true},
+		{"edge: resolved traversal rejected", "a/../../etc/x.txt", false, []bool{false}, "", true},
+		{"edge: only parent rejected", "..", false, []bool{false}, "", true},
+		{"happy: traversal allowed with allowNav", "../dir/file.txt", true, []bool{false}, "../dir/file.txt", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Path(tt.input, tt.allowNav, tt.allowAbsolute...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Path() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if filepath.ToSlash(got) != tt.want {
+				t.Errorf("Path() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		allowNav bool
+		want     string
+		wantErr  bool
+	}{
+		{"happy: basic dir", "path/to/dir", false, `path\to\dir\`, false},
+		{"happy: basic file", `path\to\file.txt`, false, `path\to\file.txt`, false},
+		{"happy: drive letter", "c:/Users/me/report.txt", false, `C:\Users\me\report.txt`, false},
+		{"happy: rooted", `\absolute\dir`, false, `\absolute\dir\`, false},
+		{"happy: trailing dots and spaces", `dir. \report.txt. `, false, `dir\report.txt`, false},
+		{"happy: with nav allow", `.\path\..\to\file.txt`, true, `.\to\file.txt`, false},
+		{"happy: no nav", `.\path\..\to\file.txt`, false, `to\file.txt`, false},
+		{"edge: cannot climb above drive", `C:\..\..\dir`, false, `C:\dir\`, false},
+		{"edge: drive relative", "C:dir", false, `C:\dir\`, false},
+		{"edge: invalid comp", "path/<>/file.txt", false, `path\file.txt`, false},
+		{"edge: empty", "", false, "", true},
+		{"edge: drive only", "C:", false, "", true},
+		{"edge: reserved dir", "path/CON/file.txt", false, "", true},
+		{"edge: reserved file with ext", "path/nul.txt", false, "", true},
+		{"edge: reserved with trailing dot", "path/aux.", false, "", true},
+		{"edge: reserved after sanitize", "path/C<O>M1", false, "", true},
+		{"edge: max length", strings.Repeat("a/", 130) + "file.txt", false, "", true},
+		{"happy: non-ASCII under max length", strings.Repeat("文件夹/", 40) + "文件.txt", false, strings.Repeat(`文件夹\`, 40) + "文件.txt", false},
+		{"edge: non-ASCII over max length", strings.Repeat("文件夹/", 70) + "文件.txt", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.WindowsPath(tt.input, tt.allowNav)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WindowsPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("WindowsPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchivePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"happy: clean nested", "docs/reports/q1.pdf", "docs/reports/q1.pdf", false},
+		{"happy: windows style", `docs\reports\q1.pdf`, "docs/reports/q1.pdf", false},
+		{"happy: windows drive", `C:\Users\me\file.txt`, "Users/me/file.txt", false},
+		{"happy: leading slashes", "//var/data/file.txt", "var/data/file.txt", false},
+		{"happy: dot and empty components", "./a//./b/", "a/b", false},
+		{"happy: dots in names", "a/..b/c..", "a/..b/c..", false},
+		{"edge: parent component", "a/../b", "", true},
+		{"edge: leading parent", "../etc/passwd", "", true},
+		{"edge: windows parent", `a\..\..\b`, "", true},
+		{"edge: empty", "", "", true},
+		{"edge: only slashes", "///", "", true},
+		{"edge: only dots", "./.", "", true},
+		{"edge: control chars", "a/\x00b", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.ArchivePath(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ArchivePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ArchivePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestUrl(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -247,6 +764,164 @@ func TestUrl(t *testing.T) {
 	}
 }
 
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxLength []int
+		want      string
+		wantErr   bool
+	}{
+		{"happy: accents and punctuation", "Héllo, World!", nil, "hello-world", false},
+		{"happy: runs collapsed", "  Go -- is   __fun__  ", nil, "go-is-fun", false},
+		{"happy: numbers kept", "Top 10 Tips (2024)", nil, "top-10-tips-2024", false},
+		{"happy: transliteration", "Straße Ærø Łódź", nil, "strasse-aero-lodz", false},
+		{"happy: compatibility forms", "ﬁle Ｎｏ①", nil, "file-no1", false},
+		{"happy: curly quotes and dashes", "Don’t Stop—Believin’", nil, "don-t-stop-believin", false},
+		{"happy: non-latin dropped", "Hello 世界 World", nil, "hello-world", false},
+		{"happy: max length cuts at word boundary", "The quick brown fox", []int{13}, "the-quick", false},
+		{"happy: max length at hyphen", "The quick brown fox", []int{9}, "the-quick", false},
+		{"happy: max length exact", "The quick", []int{9}, "the-quick", false},
+		{"happy: max length single long word", "Supercalifragilistic", []int{5}, "super", false},
+		{"happy: max length zero means no limit", "a b c", []int{0}, "a-b-c", false},
+		{"edge: empty", "", nil, "", true},
+		{"edge: only punctuation", "!!! --- ???", nil, "", true},
+		{"edge: only non-latin", "日本語", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.Slug(tt.input, tt.maxLength...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Slug() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Slug() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		sep     string
+		want    []string
+		wantErr bool
+	}{
+		{"happy: basic", "a,b,c", ",", []string{"a", "b", "c"}, false},
+		{"happy: extra spaces", "  a , b  ,c ", ",", []string{"a", "b", "c"}, false},
+		{"happy: empty elements", "a,,b, ,c,", ",", []string{"a", "b", "c"}, false},
+		{"happy: duplicates", "b,a,b, a ,c", ",", []string{"b", "a", "c"}, false},
+		{"happy: multi-char sep", "a; b;c", "; ", []string{"a", "b;c"}, false},
+		{"happy: unsafe chars", "a<b>,c", ",", []string{"a b", "c"}, false},
+		{"edge: empty input", "", ",", nil, true},
+		{"edge: only separators", " , ,, ", ",", nil, true},
+		{"edge: empty sep", "a,b", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitize.List(tt.input, tt.sep)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStable(t *testing.T) {
+	fileName := func(s string) (string, error) { return sanitize.FileName(s) }
+	preserveCase := func(s string) (string, error) {
+		return sanitize.FileName(s, sanitize.FileNameOptions{PreserveExtensionCase: true})
+	}
+	dirName := func(s string) (string, error) { return sanitize.DirName(s) }
+	hiddenDir := func(s string) (string, error) { return sanitize.DirName(s, true) }
+	path := func(s string) (string, error) { return sanitize.Path(s, false) }
+	pathNav := func(s string) (string, error) { return sanitize.Path(s, true) }
+
+	sanitizers := map[string]func(string) (string, error){
+		"FileName":          fileName,
+		"FileName preserve": preserveCase,
+		"DirName":           dirName,
+		"DirName hidden":    hiddenDir,
+		"Path":              path,
+		"Path allowNav":     pathNav,
+		"String":            func(s string) (string, error) { return sanitize.String(s) },
+		"String maxLen":     func(s string) (string, error) { return sanitize.String(s, 5) },
+		"String preserve":   func(s string) (string, error) { return sanitize.StringWithMode(s, sanitize.PreserveSpaces) },
+		"String strip all":  func(s string) (string, error) { return sanitize.StringWithMode(s, sanitize.StripAll) },
+		"ArchivePath":       sanitize.ArchivePath,
+		"Url":               func(s string) (string, error) { return sanitize.Url(s) },
+	}
+	inputs := []string{
+		"file.txt",
+		"My <Report>.PDF",
+		"file__name..tar.GZ",
+		"  spaced  name .md ",
+		".hidden",
+		"._.dir",
+		"dir/../file.txt",
+		"./a/b/c/",
+		"../up/file.txt",
+		`C:\Users\me\doc.txt`,
+		"文件.文档",
+		"CON_.txt",
+		"a_CON_/b",
+		strings.Repeat("é", 200) + ".txt",
+		strings.Repeat("a", 254) + "_b.txt",
+		strings.Repeat("目录", 100),
+		"https://example.com/a b",
+	}
+	for name, fn := range sanitizers {
+		for _, input := range inputs {
+			if _, err := fn(input); err != nil {
+				continue // Inputs rejected outright are trivially stable
+			}
+			stable, err := sanitize.IsStable(fn, input)
+			if err != nil || !stable {
+				first, _ := fn(input)
+				second, _ := fn(first)
+				t.Errorf("%s not stable for %q: %q -> %q (err %v)", name, input, first, second, err)
+			}
+		}
+	}
+
+	t.Run("Unstable sanitizer", func(t *testing.T) {
+		appendX := func(s string) (string, error) { return s + "x", nil }
+		stable, err := sanitize.IsStable(appendX, "a")
+		if err != nil || stable {
+			t.Errorf("IsStable() = %v, %v, want false, nil", stable, err)
+		}
+	})
+
+	t.Run("Error on input", func(t *testing.T) {
+		stable, err := sanitize.IsStable(dirName, "<>")
+		if err == nil || stable {
+			t.Errorf("IsStable() = %v, %v, want false and an error", stable, err)
+		}
+	})
+
+	t.Run("Error on output", func(t *testing.T) {
+		once := false
+		failSecond := func(s string) (string, error) {
+			if once {
+				return "", errors.New("boom")
+			}
+			once = true
+			return s, nil
+		}
+		stable, err := sanitize.IsStable(failSecond, "a")
+		if err == nil || !strings.Contains(err.Error(), "sanitizing output") || stable {
+			t.Errorf("IsStable() = %v, %v, want false and an output error", stable, err)
+		}
+	})
+}
+
 func TestHasFileExtension(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1,9 +1,10 @@
 // Package upload provides utilities for handling file uploads from HTTP requests.
 //
 // This package offers functions to process single or multiple file uploads, with support for sanitizing filenames,
-// validating file types and sizes, and optionally renaming files with random hex names. It integrates with the
-// filesystem package from devify-utils for safe filename handling and directory creation, and uses the go-playground/validator
-// package for struct validation. All functions include robust error handling for invalid inputs or file operations.
+// validating file types and sizes, and optionally renaming files with random hex names. Large uploads can be streamed
+// directly to disk with bounded memory use. It integrates with the filesystem package from devify-utils for safe
+// filename handling and directory creation, and uses the go-playground/validator package for struct validation.
+// All functions include robust error handling for invalid inputs or file operations.
 package upload
 
 import (
@@ -16,23 +17,58 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/devify-me/devify-utils/filesystem"
 	"github.com/go-playground/validator/v10"
 )
 
+// errSizeLimit is wrapped by saveFile when a file exceeds the number of bytes it may accept.
+var errSizeLimit = errors.New("file size exceeds maximum")
+
 // FileOperation manages configuration for file upload operations.
 //
 // It specifies the maximum file size, allowed file types, and a validator instance for validating uploaded files.
 // The Validate field must be initialized with a validator.Validate instance that includes the "allowedfiletype"
 // validation rule registered via IsAllowedFileType.
 type FileOperation struct {
-	// MaxFileSize is the maximum allowed file size in megabytes.
+	// MaxFileSize is the maximum allowed file size in bytes. It also sizes the in-memory buffer used when parsing
+	// multipart forms; larger parts are stored in temporary files. Use SetMaxFileSize to set it from a string like "10MB".
 	MaxFileSize int64
+	// MaxTotalSize is the maximum combined size in bytes of all files in a single request, or 0 for no limit.
+	// MaxFileSize applies to each file individually, so without this many small files can still exhaust disk space.
+	MaxTotalSize int64
+	// MaxFileCount is the maximum number of files in a single request, or 0 for no limit.
+	MaxFileCount int
 	// AllowedFileTypes is a slice of allowed MIME types (e.g., "image/png", "application/pdf").
 	AllowedFileTypes []string
+	// AllowedExtensions is a slice of allowed file extensions including the leading dot (e.g., ".png", ".pdf"),
+	// compared case-insensitively against the sanitized filename. An empty slice allows any extension.
+	AllowedExtensions []string
 	// Validate is the validator instance for validating UploadedFile structs.
 	Validate *validator.Validate
+	// VerifyContentType enables content-based MIME validation. The Content-Type header of a part is supplied by the
+	// client and trivially spoofed, so when this is true the MIME type of each saved file is detected from its first
+	// 512 bytes using filesystem.GetMimeTypeFromContent and checked against AllowedFileTypes. Files whose detected
+	// type is not allowed are deleted and the upload fails. The detected type, without parameters such as charset,
+	// is reported in UploadedFile.FileMimeType.
+	VerifyContentType bool
+	// ProgressFunc, if set, is called as each file is written with the number of bytes written so far and the
+	// total size of the file, which is -1 when it is not known in advance (as in UploadFilesStreaming). It is
+	// called a final time with bytesWritten equal to totalBytes once the file has been written completely.
+	ProgressFunc func(filename string, bytesWritten, totalBytes int64)
+	// RenameLength is the number of hex characters in the random names generated when files are renamed. It must be
+	// a positive even number, or 0 for the default of 32.
+	RenameLength int
+	// RenameFunc, if set, replaces random renaming with a custom naming scheme (e.g., date-prefixed or UUID-based
+	// names). It receives the sanitized original filename and returns the full storage name, including any
+	// extension, which is sanitized using filesystem.SanitizeFilename before use. It is only used when renaming.
+	RenameFunc func(original string) string
+	// VerifyExtension enables cross-checking the extension of each saved file against its MIME type (detected if
+	// VerifyContentType is set, declared by the client otherwise) using filesystem.GetMimeTypeFromExtension. This
+	// rejects, for example, an "invoice.pdf" whose content is an HTML page. See checkExtension for which mismatches
+	// are fatal and which are tolerated.
+	VerifyExtension bool
 }
 
 // UploadedFile represents metadata for an uploaded file.
@@ -76,18 +112,52 @@ func generateRandomHex(n int) (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// SetMaxFileSize sets MaxFileSize from a human-readable size string.
+//
+// The size is parsed with filesystem.ParseSize, so both decimal ("10MB", 10,000,000 bytes) and binary ("10MiB",
+// 10,485,760 bytes) units are accepted, as well as a plain number of bytes. This allows the limit to come directly
+// from configuration. MaxFileSize is left unchanged if the string is invalid.
+//
+// Example:
+//
+//	fo := &FileOperation{}
+//	if err := fo.SetMaxFileSize("10MB"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(fo.MaxFileSize) // Prints 10000000
+//
+// Parameters:
+//   - s: The maximum file size, e.g., "10MB" or "512KiB".
+//
+// Returns:
+//   - error: An error if the size cannot be parsed.
+func (f *FileOperation) SetMaxFileSize(s string) error {
+	size, err := filesystem.ParseSize(s)
+	if err != nil {
+		return fmt.Errorf("invalid max file size: %w", err)
+	}
+	f.MaxFileSize = size
+	return nil
+}
+
 // UploadFiles handles uploading multiple files from an HTTP request to the specified directory.
 //
-// The function parses the multipart form data, validates file sizes and types, sanitizes filenames using
-// filesystem.SanitizeFilename, and optionally renames files with a random 32-character hex string.
+// The function parses the multipart form data, buffering up to MaxFileSize bytes in memory, validates that each
+// file is at most MaxFileSize bytes and of an allowed type and extension, sanitizes filenames using
+// filesystem.SanitizeFilename, and optionally renames files using RenameFunc or a random hex string of RenameLength
+// characters (32 by default).
 // Each uploaded file is validated using the FileOperation.Validate instance, which must have the
 // "allowedfiletype" validation rule registered. The files are saved to the uploadDir, which is created
-// if it does not exist. An error is returned if no files are uploaded or if any operation fails.
+// if it does not exist. If VerifyContentType is set, the MIME type of each saved file is detected from its content
+// rather than taken from the client-supplied Content-Type header, and files of a disallowed type are deleted.
+// MaxFileCount and MaxTotalSize, when set, cap the number and combined size of the files in the request.
+// An error is returned if no files are uploaded or if any operation fails. Uploads are all-or-nothing: on any error,
+// every file already saved by this call is deleted before the error is returned, so no partial files are left behind.
 //
 // Example:
 //
 //	fo := &FileOperation{
-//	    MaxFileSize:     10, // 10 MB
+//	    MaxFileSize:      10 << 20, // 10 MiB
 //	    AllowedFileTypes: []string{"image/png", "image/jpeg"},
 //	    Validate:         validator.New(),
 //	}
@@ -103,21 +173,79 @@ func generateRandomHex(n int) (string, error) {
 // Parameters:
 //   - r: The HTTP request containing the multipart form data with files.
 //   - uploadDir: The directory where files will be saved (created if it does not exist).
-//   - rename: If true, files are renamed with a random 32-character hex string plus their original extension.
+//   - rename: If true, files are renamed using RenameFunc or a random hex string plus their original extension.
 //
 // Returns:
-//   - []UploadedFile: A slice of metadata for successfully uploaded files.
+//   - []UploadedFile: A slice of metadata for successfully uploaded files, or nil if an error occurred.
 //   - error: An error if the upload directory cannot be created, form parsing fails, or any file operation or validation fails.
 func (f *FileOperation) UploadFiles(r *http.Request, uploadDir string, rename bool) ([]UploadedFile, error) {
+	filesByField, err := f.UploadFilesByField(r, uploadDir, rename)
+	if err != nil {
+		return nil, err
+	}
+	var uploadedFiles []UploadedFile
+	for _, files := range filesByField {
+		uploadedFiles = append(uploadedFiles, files...)
+	}
+	return uploadedFiles, nil
+}
+
+// UploadFilesByField handles uploading multiple files from an HTTP request, grouping them by form field name.
+//
+// UploadFiles flattens the files of every form field into a single slice, so a form with separate inputs (e.g.,
+// "avatar" and "documents") cannot tell which file came from which. This function behaves exactly like
+// UploadFiles, including its limits, validation and all-or-nothing rollback, but returns the uploaded files keyed
+// by the name of the form field they were submitted under, in the order they appear in that field.
+//
+// Example:
+//
+//	fo := &FileOperation{
+//	    MaxFileSize:      10 << 20, // 10 MiB
+//	    AllowedFileTypes: []string{"image/png", "application/pdf"},
+//	    Validate:         validator.New(),
+//	}
+//	fo.Validate.RegisterValidation("allowedfiletype", fo.IsAllowedFileType)
+//	files, err := fo.UploadFilesByField(r, "uploads", true)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, f := range files["documents"] {
+//	    fmt.Println(f.FullPath) // Prints paths of the files uploaded in the "documents" field
+//	}
+//
+// Parameters:
+//   - r: The HTTP request containing the multipart form data with files.
+//   - uploadDir: The directory where files will be saved (created if it does not exist).
+//   - rename: If true, files are renamed using RenameFunc or a random hex string plus their original extension.
+//
+// Returns:
+//   - map[string][]UploadedFile: The metadata of the uploaded files keyed by form field name, or nil if an error occurred.
+//   - error: An error if the upload directory cannot be created, form parsing fails, or any file operation or validation fails.
+func (f *FileOperation) UploadFilesByField(r *http.Request, uploadDir string, rename bool) (map[string][]UploadedFile, error) {
 	if err := filesystem.CreateDirIfNotExist(uploadDir); err != nil {
 		return nil, fmt.Errorf("failed to create upload directory: %w", err)
 	}
-	if err := r.ParseMultipartForm(f.MaxFileSize << 20); err != nil {
+	if err := r.ParseMultipartForm(f.MaxFileSize); err != nil {
 		return nil, fmt.Errorf("failed to parse multipart form: %w", err)
 	}
+	if f.MaxFileCount > 0 {
+		count := 0
+		for _, fileHeaders := range r.MultipartForm.File {
+			count += len(fileHeaders)
+		}
+		if count > f.MaxFileCount {
+			return nil, fmt.Errorf("file count %d exceeds maximum %d", count, f.MaxFileCount)
+		}
+	}
+	filesByField := make(map[string][]UploadedFile)
 	var uploadedFiles []UploadedFile
-	for _, fileHeaders := range r.MultipartForm.File {
+	var totalSize int64
+	for field, fileHeaders := range r.MultipartForm.File {
 		for _, header := range fileHeaders {
+			if f.MaxTotalSize > 0 && totalSize+header.Size > f.MaxTotalSize {
+				removeUploadedFiles(uploadedFiles)
+				return nil, fmt.Errorf("total upload size exceeds maximum %d", f.MaxTotalSize)
+			}
 			uploadedFile, err := func() (*UploadedFile, error) {
 				file, err := header.Open()
 				if err != nil {
@@ -130,66 +258,345 @@ func (f *FileOperation) UploadFiles(r *http.Request, uploadDir string, rename bo
 				if header.Size > f.MaxFileSize {
 					return nil, fmt.Errorf("file size %d exceeds maximum %d", header.Size, f.MaxFileSize)
 				}
-				sanitizedName, err := filesystem.SanitizeFilename(header.Filename)
-				if err != nil {
-					return nil, fmt.Errorf("failed to sanitize filename: %w", err)
-				}
-				var encodedName string
-				if rename {
-					hexStr, err := generateRandomHex(32)
-					if err != nil {
-						return nil, fmt.Errorf("failed to generate random name: %w", err)
-					}
-					encodedName = hexStr + filepath.Ext(sanitizedName)
-				} else {
-					encodedName = sanitizedName
-				}
-				fullPath := filepath.Join(uploadDir, encodedName)
-				destFile, err := os.Create(fullPath)
-				if err != nil {
-					return nil, fmt.Errorf("failed to create destination file: %w", err)
-				}
-				defer destFile.Close()
-				_, err = io.Copy(destFile, file)
-				if err != nil {
-					return nil, fmt.Errorf("failed to write file: %w", err)
-				}
-				uploadedFile := UploadedFile{
-					OriginalName: header.Filename,
-					EncodedName:  encodedName,
-					FullPath:     fullPath,
-					FileMimeType: header.Header.Get("Content-Type"),
-					Extension:    filepath.Ext(encodedName),
-					FileSize:     header.Size,
-				}
-				if err := f.Validate.Struct(uploadedFile); err != nil {
-					return nil, fmt.Errorf("failed to validate uploaded file: %w", err)
-				}
-				return &uploadedFile, nil
+				return f.saveFile(file, header.Filename, header.Header.Get("Content-Type"), uploadDir, rename, f.MaxFileSize, header.Size)
 			}()
 			if err != nil {
-				return uploadedFiles, fmt.Errorf("failed to save uploaded file: %w", err)
+				removeUploadedFiles(uploadedFiles)
+				return nil, fmt.Errorf("failed to save uploaded file: %w", err)
 			}
+			filesByField[field] = append(filesByField[field], *uploadedFile)
 			uploadedFiles = append(uploadedFiles, *uploadedFile)
+			totalSize += uploadedFile.FileSize
 		}
 	}
 	if len(uploadedFiles) == 0 {
 		return nil, errors.New("no files uploaded")
 	}
+	return filesByField, nil
+}
+
+// UploadFilesStreaming handles uploading multiple files from an HTTP request, streaming each file to disk.
+//
+// Unlike UploadFiles, which parses the whole multipart form and buffers up to MaxFileSize bytes per file in memory
+// before copying, this function reads the request with r.MultipartReader and copies each file part directly to
+// uploadDir. At most MaxFileSize bytes (plus one, to detect oversized files) are read from each part, so memory use
+// is bounded regardless of the upload size. Non-file form fields are skipped. Filenames, renaming, content type
+// verification and validation behave as in UploadFiles. As with UploadFiles, on any error (including exceeding
+// MaxFileCount or MaxTotalSize, which are only checked as files arrive) every file already saved by this call is
+// deleted before the error is returned.
+//
+// Example:
+//
+//	fo := &FileOperation{
+//	    MaxFileSize:      1 << 30, // 1 GiB
+//	    AllowedFileTypes: []string{"video/mp4"},
+//	    Validate:         validator.New(),
+//	}
+//	fo.Validate.RegisterValidation("allowedfiletype", fo.IsAllowedFileType)
+//	files, err := fo.UploadFilesStreaming(r, "uploads", true)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, f := range files {
+//	    fmt.Println(f.FullPath) // Prints paths of uploaded files
+//	}
+//
+// Parameters:
+//   - r: The HTTP request containing the multipart form data with files.
+//   - uploadDir: The directory where files will be saved (created if it does not exist).
+//   - rename: If true, files are renamed using RenameFunc or a random hex string plus their original extension.
+//
+// Returns:
+//   - []UploadedFile: A slice of metadata for successfully uploaded files, or nil if an error occurred.
+//   - error: An error if the upload directory cannot be created, the request is not multipart, or any file operation or validation fails.
+func (f *FileOperation) UploadFilesStreaming(r *http.Request, uploadDir string, rename bool) ([]UploadedFile, error) {
+	if err := filesystem.CreateDirIfNotExist(uploadDir); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read multipart form: %w", err)
+	}
+	var uploadedFiles []UploadedFile
+	var totalSize int64
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			removeUploadedFiles(uploadedFiles)
+			return nil, fmt.Errorf("failed to read multipart part: %w", err)
+		}
+		if part.FileName() == "" {
+			part.Close()
+			continue
+		}
+		if f.MaxFileCount > 0 && len(uploadedFiles) >= f.MaxFileCount {
+			part.Close()
+			removeUploadedFiles(uploadedFiles)
+			return nil, fmt.Errorf("file count exceeds maximum %d", f.MaxFileCount)
+		}
+		limit := f.MaxFileSize
+		totalLimited := false
+		if f.MaxTotalSize > 0 && f.MaxTotalSize-totalSize < limit {
+			limit = f.MaxTotalSize - totalSize
+			totalLimited = true
+		}
+		uploadedFile, err := f.saveFile(part, part.FileName(), part.Header.Get("Content-Type"), uploadDir, rename, limit, -1)
+		part.Close()
+		if errors.Is(err, errSizeLimit) && totalLimited {
+			removeUploadedFiles(uploadedFiles)
+			return nil, fmt.Errorf("total upload size exceeds maximum %d", f.MaxTotalSize)
+		}
+		if err != nil {
+			removeUploadedFiles(uploadedFiles)
+			return nil, fmt.Errorf("failed to save uploaded file: %w", err)
+		}
+		uploadedFiles = append(uploadedFiles, *uploadedFile)
+		totalSize += uploadedFile.FileSize
+	}
+	if len(uploadedFiles) == 0 {
+		return nil, errors.New("no files uploaded")
+	}
 	return uploadedFiles, nil
 }
 
+// saveFile writes an uploaded file to uploadDir and validates its metadata.
+//
+// The filename is sanitized using filesystem.SanitizeFilename, and replaced with a storage name from renamedFile
+// if rename is true. At most limit bytes are accepted from src; if more are available,
+// the partially written file is deleted and an error wrapping errSizeLimit is returned. If VerifyContentType is set,
+// the MIME type is detected from the saved content instead of using mimeType. The saved file is deleted if any
+// later check fails. It is unexported as it is intended for internal use by UploadFilesByField and UploadFilesStreaming.
+//
+// Parameters:
+//   - src: The reader providing the file content.
+//   - filename: The original filename provided by the client.
+//   - mimeType: The MIME type provided by the client.
+//   - uploadDir: The directory where the file will be saved.
+//   - rename: If true, the file is renamed using RenameFunc or a random hex string plus its original extension.
+//   - limit: The maximum number of bytes to accept from src.
+//   - totalBytes: The expected size of the file reported to ProgressFunc, or -1 if unknown.
+//
+// Returns:
+//   - *UploadedFile: A pointer to the metadata for the saved file.
+//   - error: An error if the filename is invalid, the file is too large, or any file operation or validation fails.
+func (f *FileOperation) saveFile(src io.Reader, filename, mimeType, uploadDir string, rename bool, limit, totalBytes int64) (*UploadedFile, error) {
+	if filename == "" {
+		return nil, errors.New("filename cannot be empty")
+	}
+	sanitizedName, err := filesystem.SanitizeFilename(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sanitize filename: %w", err)
+	}
+	if ext := filepath.Ext(sanitizedName); len(f.AllowedExtensions) > 0 &&
+		!slices.ContainsFunc(f.AllowedExtensions, func(allowed string) bool { return strings.EqualFold(allowed, ext) }) {
+		return nil, fmt.Errorf("file extension %q is not allowed", ext)
+	}
+	encodedName := sanitizedName
+	if rename {
+		encodedName, err = f.renamedFile(sanitizedName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	fullPath := filepath.Join(uploadDir, encodedName)
+	destFile, err := os.Create(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create destination file: %w", err)
+	}
+	// Read one byte past the limit to detect oversized files without reading the remainder
+	var reader io.Reader = io.LimitReader(src, limit+1)
+	if f.ProgressFunc != nil {
+		reader = &progressReader{reader: reader, filename: filename, total: totalBytes, progress: f.ProgressFunc}
+	}
+	size, err := io.Copy(destFile, reader)
+	if closeErr := destFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fullPath)
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	if size > limit {
+		os.Remove(fullPath)
+		return nil, fmt.Errorf("%w %d", errSizeLimit, limit)
+	}
+	if f.ProgressFunc != nil {
+		f.ProgressFunc(filename, size, size)
+	}
+	if f.VerifyContentType {
+		mimeType, err = f.verifyContentType(fullPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if f.VerifyExtension {
+		if err := checkExtension(filepath.Ext(encodedName), mimeType); err != nil {
+			os.Remove(fullPath)
+			return nil, err
+		}
+	}
+	uploadedFile := UploadedFile{
+		OriginalName: filename,
+		EncodedName:  encodedName,
+		FullPath:     fullPath,
+		FileMimeType: mimeType,
+		Extension:    filepath.Ext(encodedName),
+		FileSize:     size,
+	}
+	if err := f.Validate.Struct(uploadedFile); err != nil {
+		os.Remove(fullPath)
+		return nil, fmt.Errorf("failed to validate uploaded file: %w", err)
+	}
+	return &uploadedFile, nil
+}
+
+// renamedFile returns the storage name for a file that is being renamed.
+//
+// If RenameFunc is set, its result is sanitized using filesystem.SanitizeFilename and used as the storage name.
+// Otherwise, the name is a random hex string of RenameLength characters (32 if RenameLength is 0) plus the original
+// extension. It is unexported as it is intended for internal use by saveFile.
+//
+// Parameters:
+//   - sanitizedName: The sanitized original filename.
+//
+// Returns:
+//   - string: The storage name for the file.
+//   - error: An error if RenameLength is invalid, RenameFunc returns an unusable name, or random generation fails.
+func (f *FileOperation) renamedFile(sanitizedName string) (string, error) {
+	if f.RenameFunc != nil {
+		name, err := filesystem.SanitizeFilename(f.RenameFunc(sanitizedName))
+		if err != nil {
+			return "", fmt.Errorf("failed to sanitize renamed filename: %w", err)
+		}
+		return name, nil
+	}
+	length := f.RenameLength
+	if length == 0 {
+		length = 32
+	}
+	if length < 0 || length%2 != 0 {
+		return "", fmt.Errorf("rename length must be a positive even number, got %d", f.RenameLength)
+	}
+	hexStr, err := generateRandomHex(length)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random name: %w", err)
+	}
+	return hexStr + filepath.Ext(sanitizedName), nil
+}
+
+// checkExtension checks that a file extension is consistent with a MIME type.
+//
+// The MIME type expected for the extension is looked up with filesystem.GetMimeTypeFromExtension, and parameters
+// such as charset are ignored on both sides. A mismatch is fatal when both types are known and differ, such as a
+// ".pdf" extension on "text/html" content. The following mismatches are tolerated rather than rejected, because
+// content sniffing cannot distinguish them from legitimate files:
+//   - The file has no extension, or an extension with no known MIME type.
+//   - The MIME type is "application/octet-stream", meaning the content could not be identified.
+//   - The MIME type is "text/plain" and the extension is a text format such as ".csv", ".json" or ".xml".
+//   - The MIME type is "application/zip" and the extension is a zip-based format such as ".docx" or ".epub".
+//
+// It is unexported as it is intended for internal use by saveFile.
+//
+// Parameters:
+//   - ext: The file extension, including the leading dot (e.g., ".pdf").
+//   - mimeType: The MIME type of the file content.
+//
+// Returns:
+//   - error: An error if the extension and MIME type disagree.
+func checkExtension(ext, mimeType string) error {
+	if ext == "" {
+		return nil
+	}
+	extType, _, _ := strings.Cut(filesystem.GetMimeTypeFromExtension(ext), ";")
+	extType = strings.TrimSpace(extType)
+	contentType, _, _ := strings.Cut(mimeType, ";")
+	contentType = strings.TrimSpace(contentType)
+	switch {
+	case extType == contentType, extType == "application/octet-stream", contentType == "application/octet-stream":
+		return nil
+	case contentType == "text/plain" && (strings.HasPrefix(extType, "text/") || extType == "application/json" ||
+		strings.HasSuffix(extType, "+json") || extType == "application/xml" || strings.HasSuffix(extType, "+xml")):
+		return nil
+	case contentType == "application/zip" && (strings.HasSuffix(extType, "+zip") || extType == "application/java-archive" ||
+		strings.HasPrefix(extType, "application/vnd.openxmlformats-officedocument.") ||
+		strings.HasPrefix(extType, "application/vnd.oasis.opendocument.")):
+		return nil
+	}
+	return fmt.Errorf("extension %q does not match content type %q (expected %q)", ext, contentType, extType)
+}
+
+// progressReader wraps a reader and reports the number of bytes read after each read.
+type progressReader struct {
+	reader   io.Reader
+	filename string
+	written  int64
+	total    int64
+	progress func(filename string, bytesWritten, totalBytes int64)
+}
+
+// Read reads from the underlying reader and reports progress if any bytes were read.
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	if n > 0 {
+		p.written += int64(n)
+		p.progress(p.filename, p.written, p.total)
+	}
+	return n, err
+}
+
+// removeUploadedFiles rolls back an aborted upload by deleting the files already saved, ignoring errors for files
+// already removed.
+//
+// It is unexported as it is intended for internal use by UploadFilesByField and UploadFilesStreaming.
+//
+// Parameters:
+//   - files: The metadata of the files to delete.
+func removeUploadedFiles(files []UploadedFile) {
+	for _, file := range files {
+		os.Remove(file.FullPath)
+	}
+}
+
+// verifyContentType detects the MIME type of a saved file from its content and checks it against AllowedFileTypes.
+//
+// Parameters such as charset are stripped from the detected type before comparison. If the type is not allowed,
+// or cannot be detected, the file is deleted. It is unexported as it is intended for internal use by saveFile.
+//
+// Parameters:
+//   - path: The path of the saved file.
+//
+// Returns:
+//   - string: The detected MIME type without parameters (e.g., "text/plain").
+//   - error: An error if the type cannot be detected or is not in AllowedFileTypes.
+func (f *FileOperation) verifyContentType(path string) (string, error) {
+	detected, err := filesystem.GetMimeTypeFromContent(path)
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to detect content type: %w", err)
+	}
+	mimeType, _, _ := strings.Cut(detected, ";")
+	mimeType = strings.TrimSpace(mimeType)
+	if !slices.Contains(f.AllowedFileTypes, mimeType) {
+		os.Remove(path)
+		return "", fmt.Errorf("detected content type %q is not allowed", mimeType)
+	}
+	return mimeType, nil
+}
+
 // UploadOneFile handles uploading a single file from an HTTP request to the specified directory.
 //
 // The function wraps UploadFiles to process a single file, ensuring exactly one file is uploaded.
-// It validates the file size, type, and filename, and optionally renames the file with a random 32-character
+// It validates the file size, type, and filename, and optionally renames the file using RenameFunc or a random
 // hex string. The file is saved to the uploadDir, which is created if it does not exist. An error is returned
 // if multiple files are uploaded or if any operation fails.
 //
 // Example:
 //
 //	fo := &FileOperation{
-//	    MaxFileSize:     5, // 5 MB
+//	    MaxFileSize:      5 << 20, // 5 MiB
 //	    AllowedFileTypes: []string{"text/plain"},
 //	    Validate:         validator.New(),
 //	}
@@ -203,7 +610,7 @@ func (f *FileOperation) UploadFiles(r *http.Request, uploadDir string, rename bo
 // Parameters:
 //   - r: The HTTP request containing the multipart form data with a single file.
 //   - uploadDir: The directory where the file will be saved (created if it does not exist).
-//   - rename: If true, the file is renamed with a random 32-character hex string plus its original extension.
+//   - rename: If true, the file is renamed using RenameFunc or a random hex string plus its original extension.
 //
 // Returns:
 //   - *UploadedFile: A pointer to the metadata for the uploaded file.
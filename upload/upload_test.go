@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -159,6 +160,648 @@ func TestFileOperation_UploadFiles(t *testing.T) {
 	}
 }
 
+func TestFileOperation_UploadFilesByField(t *testing.T) {
+	allowed := []string{"text/plain"}
+	f := &upload.FileOperation{
+		MaxFileSize:      1024,
+		AllowedFileTypes: allowed,
+		Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+	}
+	newRequest := func(parts [][2]string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("title", "not a file")
+		for _, p := range parts {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, p[0], p[1]))
+			h.Set("Content-Type", "text/plain")
+			part, _ := writer.CreatePart(h)
+			part.Write([]byte("content"))
+		}
+		writer.Close()
+		req := &http.Request{
+			Method: "POST",
+			Header: http.Header{"Content-Type": []string{writer.FormDataContentType()}},
+			Body:   io.NopCloser(body),
+		}
+		req.ContentLength = int64(body.Len())
+		return req
+	}
+
+	t.Run("Grouped by field", func(t *testing.T) {
+		uploadDir := filepath.Join(t.TempDir(), "uploads")
+		req := newRequest([][2]string{{"avatar", "me.txt"}, {"documents", "a.txt"}, {"documents", "b.txt"}})
+		got, err := f.UploadFilesByField(req, uploadDir, false)
+		if err != nil {
+			t.Fatalf("UploadFilesByField() unexpected error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("UploadFilesByField() returned %d fields, want 2", len(got))
+		}
+		if len(got["avatar"]) != 1 || got["avatar"][0].OriginalName != "me.txt" {
+			t.Errorf("UploadFilesByField() avatar = %+v, want me.txt", got["avatar"])
+		}
+		var names []string
+		for _, uf := range got["documents"] {
+			names = append(names, uf.OriginalName)
+			if !filesystem.FileExists(uf.FullPath) {
+				t.Errorf("Uploaded file does not exist: %s", uf.FullPath)
+			}
+		}
+		if !slices.Equal(names, []string{"a.txt", "b.txt"}) {
+			t.Errorf("UploadFilesByField() documents = %v, want [a.txt b.txt]", names)
+		}
+	})
+
+	t.Run("No files", func(t *testing.T) {
+		got, err := f.UploadFilesByField(newRequest(nil), filepath.Join(t.TempDir(), "uploads"), false)
+		if err == nil || !strings.Contains(err.Error(), "no files uploaded") || got != nil {
+			t.Errorf("UploadFilesByField() = %v, %v, want nil and an error containing %q", got, err, "no files uploaded")
+		}
+	})
+}
+
+func TestFileOperation_MaxFileSizeBytes(t *testing.T) {
+	uploadDir := filepath.Join(t.TempDir(), "uploads")
+	allowed := []string{"text/plain"}
+	f := &upload.FileOperation{
+		MaxFileSize:      1024,
+		AllowedFileTypes: allowed,
+		Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+	}
+
+	t.Run("At limit is accepted and buffered in memory", func(t *testing.T) {
+		req := createMultipartRequest(map[string]struct{ Content, Mime string }{"limit.txt": {Content: strings.Repeat("a", 1024), Mime: "text/plain"}})
+		got, err := f.UploadFiles(req, uploadDir, false)
+		if err != nil {
+			t.Fatalf("UploadFiles() unexpected error = %v", err)
+		}
+		if got[0].FileSize != 1024 {
+			t.Errorf("UploadFiles() FileSize = %d, want 1024", got[0].FileSize)
+		}
+		// A multipart buffer of MaxFileSize bytes keeps a file of that size in memory rather than on disk
+		part, err := req.MultipartForm.File["file"][0].Open()
+		if err != nil {
+			t.Fatalf("Open() unexpected error = %v", err)
+		}
+		defer part.Close()
+		if _, onDisk := part.(*os.File); onDisk {
+			t.Errorf("UploadFiles() spilled a file within MaxFileSize to disk; multipart buffer is too small")
+		}
+	})
+
+	t.Run("One byte over limit is rejected", func(t *testing.T) {
+		req := createMultipartRequest(map[string]struct{ Content, Mime string }{"over.txt": {Content: strings.Repeat("a", 1025), Mime: "text/plain"}})
+		_, err := f.UploadFiles(req, uploadDir, false)
+		if err == nil || !strings.Contains(err.Error(), "file size 1025 exceeds maximum 1024") {
+			t.Errorf("UploadFiles() error = %v, want file size 1025 exceeds maximum 1024", err)
+		}
+	})
+}
+
+func TestFileOperation_VerifyContentType(t *testing.T) {
+	allowed := []string{"text/plain", "image/png"}
+	f := &upload.FileOperation{
+		MaxFileSize:       10 << 20,
+		AllowedFileTypes:  allowed,
+		Validate:          setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+		VerifyContentType: true,
+	}
+	pngContent := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 16)
+	exeContent := "MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		mime     string
+		wantMime string
+		wantErr  string
+	}{
+		{"Text matches header", "notes.txt", "plain text", "text/plain", "text/plain", ""},
+		{"PNG matches header", "image.png", pngContent, "image/png", "image/png", ""},
+		{"Detected type overrides header", "image.png", pngContent, "text/plain", "image/png", ""},
+		{"Spoofed header is rejected", "setup.exe", exeContent, "text/plain", "", "detected content type \"application/octet-stream\" is not allowed"},
+		{"Empty file is rejected", "empty.txt", "", "text/plain", "", "is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := filepath.Join(t.TempDir(), "uploads")
+			req := createMultipartRequest(map[string]struct{ Content, Mime string }{tt.filename: {Content: tt.content, Mime: tt.mime}})
+			got, err := f.UploadFiles(req, uploadDir, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadFiles() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				if filesystem.FileExists(filepath.Join(uploadDir, tt.filename)) {
+					t.Errorf("UploadFiles() left rejected file %q on disk", tt.filename)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadFiles() unexpected error = %v", err)
+			}
+			if got[0].FileMimeType != tt.wantMime {
+				t.Errorf("UploadFiles() FileMimeType = %q, want %q", got[0].FileMimeType, tt.wantMime)
+			}
+		})
+	}
+}
+
+func TestFileOperation_UploadLimits(t *testing.T) {
+	allowed := []string{"text/plain"}
+	files := map[string]struct{ Content, Mime string }{
+		"a.txt": {Content: strings.Repeat("a", 100), Mime: "text/plain"},
+		"b.txt": {Content: strings.Repeat("b", 100), Mime: "text/plain"},
+		"c.txt": {Content: strings.Repeat("c", 100), Mime: "text/plain"},
+	}
+
+	tests := []struct {
+		name         string
+		maxTotalSize int64
+		maxFileCount int
+		wantLen      int
+		wantErr      string
+	}{
+		{"No limits", 0, 0, 3, ""},
+		{"Total size at limit", 300, 0, 3, ""},
+		{"Total size exceeded", 250, 0, 0, "total upload size exceeds maximum 250"},
+		{"File count at limit", 0, 3, 3, ""},
+		{"File count exceeded", 0, 2, 0, "file count 3 exceeds maximum 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := filepath.Join(t.TempDir(), "uploads")
+			f := &upload.FileOperation{
+				MaxFileSize:      1024,
+				MaxTotalSize:     tt.maxTotalSize,
+				MaxFileCount:     tt.maxFileCount,
+				AllowedFileTypes: allowed,
+				Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+			}
+			got, err := f.UploadFiles(createMultipartRequest(files), uploadDir, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadFiles() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				entries, _ := os.ReadDir(uploadDir)
+				if len(entries) != 0 {
+					t.Errorf("UploadFiles() left %d files on disk after aborting", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadFiles() unexpected error = %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("UploadFiles() len = %v, want %v", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestFileOperation_UploadFilesStreaming(t *testing.T) {
+	allowed := []string{"text/plain"}
+
+	tests := []struct {
+		name         string
+		files        map[string]struct{ Content, Mime string }
+		maxFileSize  int64
+		maxTotalSize int64
+		maxFileCount int
+		rename       bool
+		wantLen      int
+		wantErr      string
+	}{
+		{
+			name:        "Single file",
+			files:       map[string]struct{ Content, Mime string }{"test.txt": {Content: "content", Mime: "text/plain"}},
+			maxFileSize: 1024,
+			wantLen:     1,
+		},
+		{
+			name:        "Multiple files with rename",
+			files:       map[string]struct{ Content, Mime string }{"file1.txt": {Content: "content1", Mime: "text/plain"}, "file2.txt": {Content: "content2", Mime: "text/plain"}},
+			maxFileSize: 1024,
+			rename:      true,
+			wantLen:     2,
+		},
+		{
+			name:        "At limit",
+			files:       map[string]struct{ Content, Mime string }{"limit.txt": {Content: strings.Repeat("a", 1024), Mime: "text/plain"}},
+			maxFileSize: 1024,
+			wantLen:     1,
+		},
+		{
+			name:        "One byte over limit",
+			files:       map[string]struct{ Content, Mime string }{"over.txt": {Content: strings.Repeat("a", 1025), Mime: "text/plain"}},
+			maxFileSize: 1024,
+			wantErr:     "file size exceeds maximum 1024",
+		},
+		{
+			name:         "Total size exceeded",
+			files:        map[string]struct{ Content, Mime string }{"a.txt": {Content: strings.Repeat("a", 100), Mime: "text/plain"}, "b.txt": {Content: strings.Repeat("b", 100), Mime: "text/plain"}},
+			maxFileSize:  1024,
+			maxTotalSize: 150,
+			wantErr:      "total upload size exceeds maximum 150",
+		},
+		{
+			name:         "File count exceeded",
+			files:        map[string]struct{ Content, Mime string }{"a.txt": {Content: "a", Mime: "text/plain"}, "b.txt": {Content: "b", Mime: "text/plain"}},
+			maxFileSize:  1024,
+			maxFileCount: 1,
+			wantErr:      "file count exceeds maximum 1",
+		},
+		{
+			name:        "Invalid mime",
+			files:       map[string]struct{ Content, Mime string }{"test.exe": {Content: "content", Mime: "application/zip"}},
+			maxFileSize: 1024,
+			wantErr:     "failed to validate uploaded file",
+		},
+		{
+			name:        "No files",
+			files:       map[string]struct{ Content, Mime string }{},
+			maxFileSize: 1024,
+			wantErr:     "no files uploaded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := filepath.Join(t.TempDir(), "uploads")
+			f := &upload.FileOperation{
+				MaxFileSize:      tt.maxFileSize,
+				MaxTotalSize:     tt.maxTotalSize,
+				MaxFileCount:     tt.maxFileCount,
+				AllowedFileTypes: allowed,
+				Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+			}
+			got, err := f.UploadFilesStreaming(createMultipartRequest(tt.files), uploadDir, tt.rename)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadFilesStreaming() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadFilesStreaming() unexpected error = %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("UploadFilesStreaming() len = %v, want %v", len(got), tt.wantLen)
+			}
+			for _, uf := range got {
+				content, err := os.ReadFile(uf.FullPath)
+				if err != nil {
+					t.Fatalf("ReadFile() unexpected error = %v", err)
+				}
+				if int64(len(content)) != uf.FileSize {
+					t.Errorf("UploadFilesStreaming() FileSize = %d, want %d", uf.FileSize, len(content))
+				}
+				if string(content) != tt.files[uf.OriginalName].Content {
+					t.Errorf("UploadFilesStreaming() content of %q does not match upload", uf.OriginalName)
+				}
+			}
+		})
+	}
+
+	t.Run("Oversized file is not left on disk", func(t *testing.T) {
+		uploadDir := filepath.Join(t.TempDir(), "uploads")
+		f := &upload.FileOperation{
+			MaxFileSize:      10,
+			AllowedFileTypes: allowed,
+			Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+		}
+		req := createMultipartRequest(map[string]struct{ Content, Mime string }{"big.txt": {Content: strings.Repeat("a", 100), Mime: "text/plain"}})
+		if _, err := f.UploadFilesStreaming(req, uploadDir, false); err == nil {
+			t.Fatalf("UploadFilesStreaming() expected error for oversized file")
+		}
+		if filesystem.FileExists(filepath.Join(uploadDir, "big.txt")) {
+			t.Errorf("UploadFilesStreaming() left a partial file on disk")
+		}
+	})
+}
+
+func TestFileOperation_UploadRollback(t *testing.T) {
+	allowed := []string{"text/plain"}
+	f := &upload.FileOperation{
+		MaxFileSize:      1024,
+		AllowedFileTypes: allowed,
+		Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+	}
+	// Build the request by hand so the failing file is always the third of five
+	newRequest := func() *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for i, mime := range []string{"text/plain", "text/plain", "application/zip", "text/plain", "text/plain"} {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="file%d.txt"`, i+1))
+			h.Set("Content-Type", mime)
+			part, _ := writer.CreatePart(h)
+			part.Write([]byte("content"))
+		}
+		writer.Close()
+		req := &http.Request{
+			Method: "POST",
+			Header: http.Header{"Content-Type": []string{writer.FormDataContentType()}},
+			Body:   io.NopCloser(body),
+		}
+		req.ContentLength = int64(body.Len())
+		return req
+	}
+
+	uploads := map[string]func(*http.Request, string) ([]upload.UploadedFile, error){
+		"UploadFiles": func(r *http.Request, dir string) ([]upload.UploadedFile, error) {
+			return f.UploadFiles(r, dir, false)
+		},
+		"UploadFilesStreaming": func(r *http.Request, dir string) ([]upload.UploadedFile, error) {
+			return f.UploadFilesStreaming(r, dir, false)
+		},
+		"UploadFilesByField": func(r *http.Request, dir string) ([]upload.UploadedFile, error) {
+			files, err := f.UploadFilesByField(r, dir, false)
+			if files == nil {
+				return nil, err
+			}
+			return files["file"], err
+		},
+	}
+
+	for name, uploadFn := range uploads {
+		t.Run(name, func(t *testing.T) {
+			uploadDir := filepath.Join(t.TempDir(), "uploads")
+			got, err := uploadFn(newRequest(), uploadDir)
+			if err == nil || !strings.Contains(err.Error(), "failed to validate uploaded file") {
+				t.Fatalf("%s() error = %v, wantErr containing %q", name, err, "failed to validate uploaded file")
+			}
+			if got != nil {
+				t.Errorf("%s() returned %d files after rolling back", name, len(got))
+			}
+			entries, err := os.ReadDir(uploadDir)
+			if err != nil {
+				t.Fatalf("ReadDir() unexpected error = %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("%s() left %d files on disk after a mid-batch failure", name, len(entries))
+			}
+		})
+	}
+}
+
+func TestFileOperation_ProgressFunc(t *testing.T) {
+	allowed := []string{"text/plain"}
+	content := strings.Repeat("a", 100_000)
+
+	tests := []struct {
+		name      string
+		streaming bool
+		wantTotal int64
+	}{
+		{"Buffered reports known total", false, int64(len(content))},
+		{"Streaming reports unknown total", true, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			type call struct{ written, total int64 }
+			var calls []call
+			f := &upload.FileOperation{
+				MaxFileSize:      1 << 20,
+				AllowedFileTypes: allowed,
+				Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+				ProgressFunc: func(filename string, bytesWritten, totalBytes int64) {
+					if filename != "big.txt" {
+						t.Errorf("ProgressFunc() filename = %q, want %q", filename, "big.txt")
+					}
+					calls = append(calls, call{bytesWritten, totalBytes})
+				},
+			}
+			req := createMultipartRequest(map[string]struct{ Content, Mime string }{"big.txt": {Content: content, Mime: "text/plain"}})
+			var err error
+			if tt.streaming {
+				_, err = f.UploadFilesStreaming(req, t.TempDir(), false)
+			} else {
+				_, err = f.UploadFiles(req, t.TempDir(), false)
+			}
+			if err != nil {
+				t.Fatalf("upload unexpected error = %v", err)
+			}
+			if len(calls) < 2 {
+				t.Fatalf("ProgressFunc() called %d times, want at least 2", len(calls))
+			}
+			for i, c := range calls[:len(calls)-1] {
+				if i > 0 && c.written <= calls[i-1].written {
+					t.Errorf("ProgressFunc() bytesWritten not increasing: %d after %d", c.written, calls[i-1].written)
+				}
+				if c.total != tt.wantTotal {
+					t.Errorf("ProgressFunc() totalBytes = %d, want %d", c.total, tt.wantTotal)
+				}
+			}
+			if last := calls[len(calls)-1]; last.written != int64(len(content)) || last.total != int64(len(content)) {
+				t.Errorf("ProgressFunc() final call = (%d, %d), want (%d, %d)", last.written, last.total, len(content), len(content))
+			}
+		})
+	}
+}
+
+func TestFileOperation_Rename(t *testing.T) {
+	allowed := []string{"text/plain"}
+
+	tests := []struct {
+		name         string
+		renameLength int
+		renameFunc   func(original string) string
+		want         string
+		wantLen      int
+		wantErr      string
+	}{
+		{name: "Default length", wantLen: 32 + len(".txt")},
+		{name: "Custom length", renameLength: 8, wantLen: 8 + len(".txt")},
+		{name: "Long length", renameLength: 64, wantLen: 64 + len(".txt")},
+		{name: "Odd length", renameLength: 7, wantErr: "rename length must be a positive even number, got 7"},
+		{name: "Negative length", renameLength: -2, wantErr: "rename length must be a positive even number, got -2"},
+		{
+			name:       "Rename func",
+			renameFunc: func(original string) string { return "2024-01-02_" + original },
+			want:       "2024-01-02_report.txt",
+		},
+		{
+			name:         "Rename func takes precedence over length",
+			renameLength: 7,
+			renameFunc:   func(original string) string { return "fixed.txt" },
+			want:         "fixed.txt",
+		},
+		{
+			name:       "Rename func result is sanitized",
+			renameFunc: func(original string) string { return "../../etc/passwd.txt" },
+			want:       "_.._etc_passwd.txt",
+		},
+		{
+			name:       "Rename func empty result",
+			renameFunc: func(original string) string { return "" },
+			wantErr:    "failed to sanitize renamed filename",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &upload.FileOperation{
+				MaxFileSize:      1024,
+				AllowedFileTypes: allowed,
+				Validate:         setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+				RenameLength:     tt.renameLength,
+				RenameFunc:       tt.renameFunc,
+			}
+			req := createMultipartRequest(map[string]struct{ Content, Mime string }{"report.txt": {Content: "content", Mime: "text/plain"}})
+			got, err := f.UploadOneFile(req, t.TempDir(), true)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadOneFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadOneFile() unexpected error = %v", err)
+			}
+			if tt.want != "" && got.EncodedName != tt.want {
+				t.Errorf("UploadOneFile() EncodedName = %q, want %q", got.EncodedName, tt.want)
+			}
+			if tt.wantLen != 0 && len(got.EncodedName) != tt.wantLen {
+				t.Errorf("UploadOneFile() EncodedName = %q, want length %d", got.EncodedName, tt.wantLen)
+			}
+			if !filesystem.FileExists(got.FullPath) {
+				t.Errorf("Uploaded file does not exist: %s", got.FullPath)
+			}
+		})
+	}
+}
+
+func TestFileOperation_VerifyExtension(t *testing.T) {
+	allowed := []string{"text/plain", "text/html", "application/pdf", "image/png", "application/json"}
+	html := "<html><body>Please sign in</body></html>"
+	pdf := "%PDF-1.4\n%âãÏÓ\n1 0 obj\n"
+
+	tests := []struct {
+		name          string
+		filename      string
+		content       string
+		mime          string
+		verifyContent bool
+		wantErr       string
+	}{
+		{"Matching pdf", "report.pdf", pdf, "application/pdf", true, ""},
+		{"Matching html", "page.html", html, "text/html", true, ""},
+		{"HTML disguised as pdf", "invoice.pdf", html, "application/pdf", true, `extension ".pdf" does not match content type "text/html"`},
+		{"HTML disguised as png", "image.png", html, "image/png", true, `extension ".png" does not match content type "text/html"`},
+		{"Declared type mismatch", "invoice.pdf", pdf, "text/html", false, `extension ".pdf" does not match content type "text/html"`},
+		{"Tolerated text format", "data.json", `{"a": 1}`, "application/json", true, ""},
+		{"Tolerated unknown extension", "notes.unknownext", "plain text", "text/plain", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			f := &upload.FileOperation{
+				MaxFileSize:       1024,
+				AllowedFileTypes:  allowed,
+				Validate:          setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+				VerifyContentType: tt.verifyContent,
+				VerifyExtension:   true,
+			}
+			req := createMultipartRequest(map[string]struct{ Content, Mime string }{tt.filename: {Content: tt.content, Mime: tt.mime}})
+			_, err := f.UploadOneFile(req, uploadDir, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadOneFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				if filesystem.FileExists(filepath.Join(uploadDir, tt.filename)) {
+					t.Errorf("UploadOneFile() left rejected file %q on disk", tt.filename)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("UploadOneFile() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFileOperation_AllowedExtensions(t *testing.T) {
+	allowed := []string{"text/plain"}
+
+	tests := []struct {
+		name       string
+		extensions []string
+		filename   string
+		wantErr    string
+	}{
+		{"Empty list allows any", nil, "script.php", ""},
+		{"Allowed extension", []string{".txt", ".md"}, "notes.txt", ""},
+		{"Case-insensitive", []string{".txt"}, "NOTES.TXT", ""},
+		{"Disallowed extension", []string{".txt"}, "shell.php", `file extension ".php" is not allowed`},
+		{"Double extension", []string{".txt"}, "notes.txt.sh", `file extension ".sh" is not allowed`},
+		{"No extension", []string{".txt"}, "README", `file extension "" is not allowed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			f := &upload.FileOperation{
+				MaxFileSize:       1024,
+				AllowedFileTypes:  allowed,
+				AllowedExtensions: tt.extensions,
+				Validate:          setupValidator(&upload.FileOperation{AllowedFileTypes: allowed}),
+			}
+			req := createMultipartRequest(map[string]struct{ Content, Mime string }{tt.filename: {Content: "content", Mime: "text/plain"}})
+			_, err := f.UploadOneFile(req, uploadDir, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UploadOneFile() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				if entries, _ := os.ReadDir(uploadDir); len(entries) != 0 {
+					t.Errorf("UploadOneFile() wrote a file with a disallowed extension")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("UploadOneFile() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFileOperation_SetMaxFileSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		want    int64
+		wantErr string
+	}{
+		{"Decimal megabytes", "10MB", 10_000_000, ""},
+		{"Binary mebibytes", "10MiB", 10 << 20, ""},
+		{"Plain bytes", "2048", 2048, ""},
+		{"Invalid unit", "10 parsecs", 5, "invalid max file size"},
+		{"Empty", "", 5, "invalid max file size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &upload.FileOperation{MaxFileSize: 5}
+			err := f.SetMaxFileSize(tt.size)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SetMaxFileSize() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("SetMaxFileSize() unexpected error = %v", err)
+			}
+			if f.MaxFileSize != tt.want {
+				t.Errorf("SetMaxFileSize() MaxFileSize = %d, want %d", f.MaxFileSize, tt.want)
+			}
+		})
+	}
+}
+
 func TestFileOperation_UploadOneFile(t *testing.T) {
 	tempDir := t.TempDir()
 	uploadDir := filepath.Join(tempDir, "Uploads")
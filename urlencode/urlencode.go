@@ -0,0 +1,136 @@
+// Package urlencode provides utilities for encoding Go structs as URL-encoded form data.
+//
+// This package converts structs into url.Values for use with application/x-www-form-urlencoded request bodies
+// or query strings, using reflection and `form` struct tags to control field names.
+// All functions include error handling for unsupported input and field types.
+package urlencode
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Marshal converts a struct into url.Values using reflection and `form` struct tags.
+//
+// Each exported field is encoded under the name given by its `form` tag, or under the field name if no tag is set. A
+// tag of "-" skips the field, and the ",omitempty" option skips the field when it has its zero value. Strings,
+// integers, unsigned integers, floats, booleans, and types implementing encoding.TextMarshaler (e.g., time.Time) are
+// supported, as are pointers to them (nil pointers are skipped). Slices and arrays of these types or of pointers to
+// them are encoded as repeated keys, skipping nil elements. Any other field type (maps, nested structs, channels, etc.)
+// results in an error.
+//
+// Example:
+//
+//	type Search struct {
+//	    Query string   `form:"q"`
+//	    Page  int      `form:"page,omitempty"`
+//	    Tags  []string `form:"tag"`
+//	}
+//	values, err := Marshal(Search{Query: "go", Tags: []string{"web", "api"}})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(values.Encode()) // Prints "q=go&tag=web&tag=api"
+//
+// Parameters:
+//   - v: The struct (or non-nil pointer to a struct) to encode.
+//
+// Returns:
+//   - url.Values: The encoded form values.
+//   - error: An error if v is not a struct or a field has an unsupported type.
+func Marshal(v any) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("data cannot be nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("data must be a struct or pointer to a struct")
+	}
+	values := url.Values{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+		if err := encodeField(values, name, fv); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return values, nil
+}
+
+// encodeField adds the value of a field to values under name, repeating the key for slices and arrays.
+func encodeField(values url.Values, name string, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if _, ok := v.Interface().(encoding.TextMarshaler); !ok && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			s, err := formatValue(elem)
+			if err != nil {
+				return err
+			}
+			values.Add(name, s)
+		}
+		return nil
+	}
+	s, err := formatValue(v)
+	if err != nil {
+		return err
+	}
+	values.Add(name, s)
+	return nil
+}
+
+// formatValue converts a scalar value to its form representation.
+func formatValue(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
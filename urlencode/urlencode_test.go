@@ -0,0 +1,115 @@
+package urlencode_test
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devify-me/devify-utils/urlencode"
+)
+
+type searchForm struct {
+	Query    string   `form:"q"`
+	Page     int      `form:"page,omitempty"`
+	Tags     []string `form:"tag"`
+	Score    float64
+	Active   bool      `form:"active"`
+	Limit    *uint     `form:"limit"`
+	Since    time.Time `form:"since,omitempty"`
+	Internal string    `form:"-"`
+	private  string
+}
+
+func TestMarshal(t *testing.T) {
+	limit := uint(50)
+	one, two := 1, 2
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		data    any
+		want    url.Values
+		wantErr string
+	}{
+		{
+			name: "Struct with slice and custom tags",
+			data: searchForm{Query: "go lang", Tags: []string{"web", "api"}, Score: 1.5, Active: true, Internal: "x", private: "y"},
+			want: url.Values{
+				"q":      {"go lang"},
+				"tag":    {"web", "api"},
+				"Score":  {"1.5"},
+				"active": {"true"},
+			},
+		},
+		{
+			name: "Pointer with optional fields set",
+			data: &searchForm{Query: "go", Page: 2, Limit: &limit, Since: since},
+			want: url.Values{
+				"q":      {"go"},
+				"page":   {"2"},
+				"Score":  {"0"},
+				"active": {"false"},
+				"limit":  {"50"},
+				"since":  {"2024-01-02T03:04:05Z"},
+			},
+		},
+		{
+			name: "Array field",
+			data: struct {
+				IDs [3]int `form:"id"`
+			}{IDs: [3]int{1, 2, 3}},
+			want: url.Values{"id": {"1", "2", "3"}},
+		},
+		{
+			name: "Slice of pointers",
+			data: struct {
+				IDs   []*int       `form:"id"`
+				Times []*time.Time `form:"t"`
+			}{IDs: []*int{&one, nil, &two}, Times: []*time.Time{&since}},
+			want: url.Values{"id": {"1", "2"}, "t": {"2024-01-02T03:04:05Z"}},
+		},
+		{
+			name:    "Nil pointer",
+			data:    (*searchForm)(nil),
+			wantErr: "data cannot be nil",
+		},
+		{
+			name:    "Not a struct",
+			data:    map[string]string{"a": "b"},
+			wantErr: "data must be a struct",
+		},
+		{
+			name: "Unsupported field type",
+			data: struct {
+				Meta map[string]string
+			}{Meta: map[string]string{}},
+			wantErr: "field Meta: unsupported type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := urlencode.Marshal(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Marshal() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Marshal() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Marshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Encoded output should be usable directly as a form body
+	values, _ := urlencode.Marshal(searchForm{Query: "a&b", Tags: []string{"x"}})
+	if got := values.Encode(); got != "Score=0&active=false&q=a%26b&tag=x" {
+		t.Errorf("Marshal().Encode() = %q", got)
+	}
+}
@@ -7,9 +7,14 @@
 package xml
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"slices"
+	"unicode"
 
 	"github.com/devify-me/devify-utils/fileio"
 )
@@ -54,6 +59,156 @@ func Marshal(data any) ([]byte, error) {
 	return append(header, output...), nil
 }
 
+// MarshalIndent serializes the given data to indented, human-readable XML format as a byte slice with an XML header.
+//
+// It behaves like Marshal, but uses xml.MarshalIndent so that each nested element begins on a new line starting
+// with prefix followed by one copy of indent per nesting level. The same XML header is prepended, and the same
+// nil and empty-output checks apply.
+//
+// Example:
+//
+//	data := Person{Name: "Alice", Age: 30}
+//	output, err := MarshalIndent(data, "", "  ")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(output))
+//	// Prints:
+//	// <?xml version="1.0" encoding="UTF-8"?>
+//	// <Person>
+//	//   <name>Alice</name>
+//	//   <age>30</age>
+//	// </Person>
+//
+// Parameters:
+//   - data: The data to serialize to XML (must be compatible with encoding/xml, e.g., structs with XML tags).
+//   - prefix: The string written at the start of each line after the first element.
+//   - indent: The string used for each level of indentation (e.g., "  " or "\t").
+//
+// Returns:
+//   - []byte: The indented XML-encoded data with the XML header as a byte slice.
+//   - error: An error if the data is nil, cannot be marshaled, or results in empty XML.
+func MarshalIndent(data any, prefix, indent string) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data cannot be nil")
+	}
+	output, err := xml.MarshalIndent(data, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, errors.New("marshaled XML is empty")
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+// MarshalWithRoot serializes the given data to XML with an XML header, using rootName as the root element.
+//
+// Marshal names the root element after the Go type (or its XMLName field), which produces awkward roots for
+// anonymous or generic types and no single root at all for slices. This function always uses rootName instead:
+// structs and scalars are encoded as the root element itself, slices and arrays are encoded as a sequence of child
+// elements (each named as by Marshal) inside the root, and maps with string keys are encoded as one child element
+// per entry, named after the key, in sorted key order. Map keys must therefore be valid XML names.
+//
+// Example:
+//
+//	data := map[string]any{"name": "Alice", "age": 30}
+//	output, err := MarshalWithRoot(data, "person")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(output)) // Prints `<?xml version="1.0" encoding="UTF-8"?><person><age>30</age><name>Alice</name></person>`
+//
+// Parameters:
+//   - data: The data to serialize to XML (a struct, scalar, slice, array, or map with string keys).
+//   - rootName: The name of the root element (must be a valid XML name, e.g., "users" or "config-v2").
+//
+// Returns:
+//   - []byte: The XML-encoded data with the XML header as a byte slice.
+//   - error: An error if the data is nil, rootName or a map key is not a valid XML name, or the data cannot be marshaled.
+func MarshalWithRoot(data any, rootName string) ([]byte, error) {
+	if rootName == "" {
+		return nil, errors.New("root element name cannot be empty")
+	}
+	if !isValidName(rootName) {
+		return nil, fmt.Errorf("invalid XML element name %q", rootName)
+	}
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() == reflect.Pointer {
+		return nil, errors.New("data cannot be nil")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	root := xml.StartElement{Name: xml.Name{Local: rootName}}
+	switch {
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		if err := enc.EncodeToken(root); err != nil {
+			return nil, err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.Encode(v.Index(i).Interface()); err != nil {
+				return nil, err
+			}
+		}
+		if err := enc.EncodeToken(root.End()); err != nil {
+			return nil, err
+		}
+	case v.Kind() == reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys must be strings, got %s", v.Type().Key())
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		slices.Sort(keys)
+		if err := enc.EncodeToken(root); err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if !isValidName(k) {
+				return nil, fmt.Errorf("map key %q is not a valid XML element name", k)
+			}
+			value := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+			if err := enc.EncodeElement(value.Interface(), xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+				return nil, err
+			}
+		}
+		if err := enc.EncodeToken(root.End()); err != nil {
+			return nil, err
+		}
+	default:
+		if err := enc.EncodeElement(v.Interface(), root); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isValidName reports whether name is a valid, unprefixed XML element name.
+func isValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // Unmarshal parses XML data into the provided destination.
 //
 // The destination must be a non-nil pointer to a struct or other type compatible with encoding/xml.
@@ -92,7 +247,7 @@ func Unmarshal(data []byte, dest any) error {
 
 // ReadFile reads an XML file from the specified path and unmarshals it into the provided destination.
 //
-// The function validates that the file path has a ".xml" extension and exists using fileio.ValidatePath.
+// The function validates that the file path has a ".xml" extension and exists using fileio.ValidateReadPath.
 // It checks that the file is not empty before attempting to unmarshal the data into the destination,
 // which must be a non-nil pointer to a struct or other type compatible with encoding/xml.
 //
@@ -131,7 +286,7 @@ func ReadFile(path string, dest any) error {
 
 // WriteFile serializes the given data to XML and writes it to a file at the specified path.
 //
-// The function validates that the file path has a ".xml" extension using fileio.ValidatePath and ensures
+// The function validates that the file path has a ".xml" extension using fileio.ValidateWritePath and ensures
 // the parent directories exist using fileio.EnsureDir. The data is marshaled to XML with the standard XML header,
 // and the resulting bytes are written to the file with the specified permissions (defaulting to 0600 if not provided).
 // If the data cannot be marshaled or the file cannot be written, an error is returned.
@@ -164,6 +319,45 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	if err != nil {
 		return err
 	}
+	return writeFile(path, output, perm)
+}
+
+// WriteFileIndent serializes the given data to indented XML and writes it to a file at the specified path.
+//
+// It behaves like WriteFile, but formats the output with MarshalIndent so that files on disk are pretty-printed
+// and easy to diff. The file ends with a trailing newline.
+//
+// Example:
+//
+//	data := Person{Name: "Alice", Age: 30}
+//	err := WriteFileIndent(data, "person.xml", "", "  ", 0o644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - data: The data to serialize to XML (must be compatible with encoding/xml, e.g., structs with XML tags).
+//   - path: The file path where the XML data will be written.
+//   - prefix: The string written at the start of each line after the first element.
+//   - indent: The string used for each level of indentation (e.g., "  " or "\t").
+//   - perm: Optional file permission mode (os.FileMode). Defaults to 0600 if not provided.
+//
+// Returns:
+//   - error: An error if the path is invalid, data cannot be marshaled, directories cannot be created,
+//     or the file cannot be written.
+func WriteFileIndent(data any, path string, prefix, indent string, perm ...os.FileMode) error {
+	if err := fileio.ValidateWritePath(path, ".xml"); err != nil {
+		return err
+	}
+	output, err := MarshalIndent(data, prefix, indent)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(output, '\n'), perm)
+}
+
+// writeFile writes output to path, creating parent directories and using perm (default 0600).
+func writeFile(path string, output []byte, perm []os.FileMode) error {
 	if err := fileio.EnsureDir(path, 0o755); err != nil {
 		return err
 	}
@@ -173,3 +367,33 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	}
 	return os.WriteFile(path, output, fileMode)
 }
+
+// Serializer implements fileio.Serializer for XML using the functions of this package.
+//
+// It is registered with fileio.Register for the ".xml" extension when this package is imported, so that
+// fileio.ReadAuto can read XML files.
+type Serializer struct{}
+
+// Marshal serializes the given data to XML using Marshal.
+func (Serializer) Marshal(data any) ([]byte, error) {
+	return Marshal(data)
+}
+
+// Unmarshal parses XML data into the provided destination using Unmarshal.
+func (Serializer) Unmarshal(data []byte, dest any) error {
+	return Unmarshal(data, dest)
+}
+
+// ReadFile reads a XML file into the provided destination using ReadFile.
+func (Serializer) ReadFile(path string, dest any) error {
+	return ReadFile(path, dest)
+}
+
+// WriteFile writes the given data to a XML file using WriteFile.
+func (Serializer) WriteFile(data any, path string, perm ...os.FileMode) error {
+	return WriteFile(data, path, perm...)
+}
+
+func init() {
+	fileio.Register(".xml", Serializer{})
+}
@@ -1,6 +1,7 @@
 package xml_test
 
 import (
+	stdxml "encoding/xml"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -59,6 +60,154 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalIndent(t *testing.T) {
+	type address struct {
+		City string `xml:"city"`
+	}
+	type person struct {
+		XMLName stdxml.Name `xml:"person"`
+		Name    string      `xml:"name"`
+		Address address     `xml:"address"`
+	}
+
+	tests := []struct {
+		name    string
+		data    any
+		prefix  string
+		indent  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "Nested elements are indented",
+			data:   person{Name: "Alice", Address: address{City: "Lisbon"}},
+			indent: "  ",
+			want: stdxml.Header + `<person>
+  <name>Alice</name>
+  <address>
+    <city>Lisbon</city>
+  </address>
+</person>`,
+		},
+		{
+			name:   "Prefix and tab indent",
+			data:   testStruct{Name: "Bob", Age: 25},
+			prefix: "> ",
+			indent: "\t",
+			want:   stdxml.Header + "> <testStruct>\n> \t<name>Bob</name>\n> \t<age>25</age>\n> </testStruct>",
+		},
+		{name: "Nil data", data: nil, indent: "  ", wantErr: "data cannot be nil"},
+		{name: "Empty output", data: []testStruct{}, indent: "  ", wantErr: "marshaled XML is empty"},
+		{name: "Unmarshalable data", data: make(chan int), indent: "  ", wantErr: "xml: unsupported type: chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.MarshalIndent(tt.data, tt.prefix, tt.indent)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MarshalIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MarshalIndent() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalIndent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalWithRoot(t *testing.T) {
+	type config[T any] struct {
+		Value T `xml:"value"`
+	}
+	type named struct {
+		XMLName stdxml.Name `xml:"named"`
+		Name    string      `xml:"name"`
+	}
+	var nilStruct *testStruct
+
+	tests := []struct {
+		name     string
+		data     any
+		rootName string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "Generic struct",
+			data:     config[int]{Value: 8080},
+			rootName: "config",
+			want:     "<config><value>8080</value></config>",
+		},
+		{
+			name:     "Overrides XMLName",
+			data:     &named{Name: "Alice"},
+			rootName: "person",
+			want:     "<person><name>Alice</name></person>",
+		},
+		{
+			name:     "Slice wrapped in root",
+			data:     []testStruct{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 25}},
+			rootName: "users",
+			want:     "<users><testStruct><name>Alice</name><age>30</age></testStruct><testStruct><name>Bob</name><age>25</age></testStruct></users>",
+		},
+		{
+			name:     "Empty slice",
+			data:     []testStruct{},
+			rootName: "users",
+			want:     "<users></users>",
+		},
+		{
+			name:     "Map with sorted keys",
+			data:     map[string]any{"name": "Alice", "age": 30, "address": testStruct{Name: "x"}},
+			rootName: "person",
+			want:     "<person><address><name>x</name><age>0</age></address><age>30</age><name>Alice</name></person>",
+		},
+		{
+			name:     "Scalar",
+			data:     "hello & goodbye",
+			rootName: "message",
+			want:     "<message>hello &amp; goodbye</message>",
+		},
+		{
+			name:     "Name with digits dots and hyphens",
+			data:     42,
+			rootName: "config-v2.1",
+			want:     "<config-v2.1>42</config-v2.1>",
+		},
+		{name: "Empty root name", data: 1, rootName: "", wantErr: "root element name cannot be empty"},
+		{name: "Root starting with digit", data: 1, rootName: "1root", wantErr: `invalid XML element name "1root"`},
+		{name: "Root with space", data: 1, rootName: "my root", wantErr: `invalid XML element name "my root"`},
+		{name: "Nil data", data: nil, rootName: "root", wantErr: "data cannot be nil"},
+		{name: "Nil pointer", data: nilStruct, rootName: "root", wantErr: "data cannot be nil"},
+		{name: "Invalid map key", data: map[string]int{"bad key": 1}, rootName: "root", wantErr: `map key "bad key" is not a valid XML element name`},
+		{name: "Non-string map keys", data: map[int]string{1: "a"}, rootName: "root", wantErr: "map keys must be strings, got int"},
+		{name: "Unmarshalable data", data: make(chan int), rootName: "root", wantErr: "xml: unsupported type: chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.MarshalWithRoot(tt.data, tt.rootName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MarshalWithRoot() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MarshalWithRoot() unexpected error = %v", err)
+			}
+			if want := stdxml.Header + tt.want; string(got) != want {
+				t.Errorf("MarshalWithRoot() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -315,3 +464,39 @@ func TestWriteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFileIndent(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "data", "person.xml")
+
+	if err := xml.WriteFileIndent(testStruct{Name: "Alice", Age: 30}, path, "", "  ", 0o644); err != nil {
+		t.Fatalf("WriteFileIndent() unexpected error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	want := stdxml.Header + "<testStruct>\n  <name>Alice</name>\n  <age>30</age>\n</testStruct>\n"
+	if string(got) != want {
+		t.Errorf("WriteFileIndent() wrote %q, want %q", got, want)
+	}
+	var back testStruct
+	if err := xml.ReadFile(path, &back); err != nil || back != (testStruct{Name: "Alice", Age: 30}) {
+		t.Errorf("ReadFile() after WriteFileIndent() = %+v, %v", back, err)
+	}
+
+	errTests := []struct {
+		name    string
+		data    any
+		path    string
+		wantErr string
+	}{
+		{"Invalid extension", testStruct{Name: "A"}, filepath.Join(tempDir, "a.txt"), "file must have .xml extension"},
+		{"Nil data", nil, filepath.Join(tempDir, "nil.xml"), "data cannot be nil"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := xml.WriteFileIndent(tt.data, tt.path, "", "  ")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WriteFileIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
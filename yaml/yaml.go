@@ -7,12 +7,16 @@
 package yaml
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/devify-me/devify-utils/fileio"
+	"github.com/devify-me/devify-utils/filesystem"
 	yamlv3 "gopkg.in/yaml.v3"
 )
 
@@ -57,6 +61,56 @@ func Marshal(data any) ([]byte, error) {
 	return output, nil
 }
 
+// MarshalIndent serializes the given data to YAML format as a byte slice, indenting nested blocks by spaces.
+//
+// It behaves like Marshal, which always uses gopkg.in/yaml.v3's default of 4 spaces, but configures a
+// yaml.Encoder with SetIndent so the output can match a project's formatting rules (e.g., 2 spaces).
+// gopkg.in/yaml.v3 cannot emit a 1-space indent (it silently widens it to 2), so spaces must be between 2 and 9.
+// Panics during marshaling are recovered and converted to errors, as in Marshal.
+//
+// Example:
+//
+//	data := map[string]any{"server": map[string]int{"port": 8080}}
+//	output, err := MarshalIndent(data, 2)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(output)) // Prints "server:\n  port: 8080\n"
+//
+// Parameters:
+//   - data: The data to serialize to YAML (e.g., structs, maps, or other types supported by gopkg.in/yaml.v3).
+//   - spaces: The number of spaces per indentation level, between 2 and 9.
+//
+// Returns:
+//   - []byte: The YAML-encoded data as a byte slice.
+//   - error: An error if the data is nil, spaces is out of range, or the data cannot be marshaled.
+func MarshalIndent(data any, spaces int) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data cannot be nil")
+	}
+	if spaces < 2 || spaces > 9 {
+		return nil, fmt.Errorf("indent must be between 2 and 9 spaces, got %d", spaces)
+	}
+	var buf bytes.Buffer
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		enc := yamlv3.NewEncoder(&buf)
+		enc.SetIndent(spaces)
+		if err = enc.Encode(data); err == nil {
+			err = enc.Close()
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // Unmarshal parses YAML data into the provided destination.
 //
 // The destination must be a non-nil pointer to a struct, map, or other type supported by gopkg.in/yaml.v3.
@@ -163,6 +217,51 @@ func ReadFile(path string, dest any) error {
 //   - error: An error if the path is invalid, data cannot be marshaled, directories cannot be created,
 //     or the file cannot be written.
 func WriteFile(data any, path string, perm ...os.FileMode) error {
+	if err := validateWritePath(path); err != nil {
+		return err
+	}
+	output, err := Marshal(data)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, output, perm)
+}
+
+// WriteFileIndent serializes the given data to YAML with custom indentation and writes it to a file at the specified path.
+//
+// It behaves like WriteFile, but formats the output with MarshalIndent, for projects whose lint rules require
+// an indentation other than gopkg.in/yaml.v3's default of 4 spaces.
+//
+// Example:
+//
+//	data := map[string]any{"server": map[string]int{"port": 8080}}
+//	err := WriteFileIndent(data, "config.yaml", 2, 0o644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - data: The data to serialize to YAML (e.g., structs, maps, or other types supported by gopkg.in/yaml.v3).
+//   - path: The file path where the YAML data will be written (must have .yaml or .yml extension).
+//   - spaces: The number of spaces per indentation level, between 2 and 9.
+//   - perm: Optional file permission mode (os.FileMode). Defaults to 0600 if not provided.
+//
+// Returns:
+//   - error: An error if the path is invalid, spaces is out of range, data cannot be marshaled,
+//     directories cannot be created, or the file cannot be written.
+func WriteFileIndent(data any, path string, spaces int, perm ...os.FileMode) error {
+	if err := validateWritePath(path); err != nil {
+		return err
+	}
+	output, err := MarshalIndent(data, spaces)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, output, perm)
+}
+
+// validateWritePath checks that path is a valid destination for a YAML file.
+func validateWritePath(path string) error {
 	if path == "" || path == "." {
 		return errors.New("path cannot be empty or root")
 	}
@@ -173,13 +272,11 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	if ext != ".yaml" && ext != ".yml" {
 		return errors.New("file must have .yaml or .yml extension")
 	}
-	if err := fileio.ValidateWritePath(path, ext); err != nil {
-		return err
-	}
-	output, err := Marshal(data)
-	if err != nil {
-		return err
-	}
+	return fileio.ValidateWritePath(path, ext)
+}
+
+// writeFile writes output to path, creating parent directories and using perm (default 0600).
+func writeFile(path string, output []byte, perm []os.FileMode) error {
 	if err := fileio.EnsureDir(path, 0o755); err != nil {
 		return err
 	}
@@ -189,3 +286,150 @@ func WriteFile(data any, path string, perm ...os.FileMode) error {
 	}
 	return os.WriteFile(path, output, fileMode)
 }
+
+// Node is a node in a YAML document tree, as produced by gopkg.in/yaml.v3.
+//
+// It is an alias, so values can be passed to and from gopkg.in/yaml.v3 directly.
+type Node = yamlv3.Node
+
+// UpdateFile edits a YAML file in place while preserving its comments, key ordering, and indentation.
+//
+// Unlike a ReadFile and WriteFile round trip through a struct, which drops comments and reorders keys, this function
+// decodes the file into a Node tree, passes the document node to mutate, and re-encodes the modified tree. Comments
+// attached to nodes that mutate keeps are written back, and the indentation width of the original file is detected
+// and reused (defaulting to 4 spaces, the gopkg.in/yaml.v3 default). Files indented with a single space are written
+// with 2, the narrowest indentation gopkg.in/yaml.v3 can emit. Quoting and blank lines may still be normalized.
+// The file is replaced atomically with filesystem.WriteFileAtomic and keeps its permissions. If mutate returns an
+// error, the file is left untouched. Files containing more than one YAML document are not supported.
+//
+// Example:
+//
+//	err := UpdateFile("config.yaml", func(doc *Node) error {
+//	    root := doc.Content[0] // The top-level mapping
+//	    for i := 0; i < len(root.Content); i += 2 {
+//	        if root.Content[i].Value == "port" {
+//	            root.Content[i+1].Value = "9090"
+//	        }
+//	    }
+//	    return nil
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: The file path of the YAML file to update (must have .yaml or .yml extension).
+//   - mutate: A function that modifies the document node in place.
+//
+// Returns:
+//   - error: An error if the path is invalid, the file is empty or cannot be parsed, mutate is nil or fails,
+//     or the file cannot be written.
+func UpdateFile(path string, mutate func(node *Node) error) error {
+	if path == "" || path == "." {
+		return errors.New("path cannot be empty or root")
+	}
+	if len(path) > 4096 {
+		return errors.New("path too long")
+	}
+	if mutate == nil {
+		return errors.New("mutate function cannot be nil")
+	}
+	ext := filepath.Ext(path)
+	if err := fileio.ValidateReadPath(path, ext); err != nil {
+		return err
+	}
+	if ext != ".yaml" && ext != ".yml" {
+		return errors.New("file must have .yaml or .yml extension")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("file is empty")
+	}
+
+	dec := yamlv3.NewDecoder(bytes.NewReader(data))
+	var doc Node
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	var extra Node
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return errors.New("multi-document YAML files are not supported")
+	}
+	if err := mutate(&doc); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return filesystem.WriteFileAtomic(path, buf.Bytes(), info.Mode().Perm())
+}
+
+// detectIndent returns the smallest non-zero indentation of a content line in data, or 4 if there is none.
+// An indentation of 1 is widened to 2, since gopkg.in/yaml.v3 cannot emit narrower indentation.
+func detectIndent(data []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 && (indent == 0 || n < indent) {
+			indent = n
+		}
+	}
+	switch {
+	case indent == 0 || indent > 9:
+		return 4
+	case indent == 1:
+		return 2
+	}
+	return indent
+}
+
+// Serializer implements fileio.Serializer for YAML using the functions of this package.
+//
+// It is registered with fileio.Register for the ".yaml" and ".yml" extensions when this package is imported, so that
+// fileio.ReadAuto can read YAML files.
+type Serializer struct{}
+
+// Marshal serializes the given data to YAML using Marshal.
+func (Serializer) Marshal(data any) ([]byte, error) {
+	return Marshal(data)
+}
+
+// Unmarshal parses YAML data into the provided destination using Unmarshal.
+func (Serializer) Unmarshal(data []byte, dest any) error {
+	return Unmarshal(data, dest)
+}
+
+// ReadFile reads a YAML file into the provided destination using ReadFile.
+func (Serializer) ReadFile(path string, dest any) error {
+	return ReadFile(path, dest)
+}
+
+// WriteFile writes the given data to a YAML file using WriteFile.
+func (Serializer) WriteFile(data any, path string, perm ...os.FileMode) error {
+	return WriteFile(data, path, perm...)
+}
+
+func init() {
+	fileio.Register(".yaml", Serializer{})
+	fileio.Register(".yml", Serializer{})
+}
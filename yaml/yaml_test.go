@@ -1,6 +1,7 @@
 package yaml_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -60,6 +61,64 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalIndent(t *testing.T) {
+	type server struct {
+		Host  string   `yaml:"host"`
+		Ports []int    `yaml:"ports"`
+		Tags  []string `yaml:"tags,omitempty"`
+	}
+	data := map[string]server{"server": {Host: "localhost", Ports: []int{80, 443}}}
+
+	tests := []struct {
+		name    string
+		data    any
+		spaces  int
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "Two spaces",
+			data:   data,
+			spaces: 2,
+			want:   "server:\n  host: localhost\n  ports:\n    - 80\n    - 443\n",
+		},
+		{
+			name:   "Four spaces matches Marshal",
+			data:   data,
+			spaces: 4,
+			want:   "server:\n    host: localhost\n    ports:\n        - 80\n        - 443\n",
+		},
+		{name: "Nil data", data: nil, spaces: 2, wantErr: "data cannot be nil"},
+		{name: "One space", data: data, spaces: 1, wantErr: "indent must be between 2 and 9 spaces, got 1"},
+		{name: "Zero spaces", data: data, spaces: 0, wantErr: "indent must be between 2 and 9 spaces, got 0"},
+		{name: "Too many spaces", data: data, spaces: 10, wantErr: "indent must be between 2 and 9 spaces, got 10"},
+		{name: "Unmarshalable data", data: make(chan int), spaces: 2, wantErr: "cannot marshal type: chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yaml.MarshalIndent(tt.data, tt.spaces)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MarshalIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MarshalIndent() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalIndent() = %q, want %q", got, tt.want)
+			}
+			if tt.spaces == 4 {
+				if plain, _ := yaml.Marshal(tt.data); string(plain) != string(got) {
+					t.Errorf("Marshal() = %q, MarshalIndent(4) = %q", plain, got)
+				}
+			}
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -353,3 +412,166 @@ func TestWriteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFileIndent(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "config", "app.yaml")
+	data := map[string]testStruct{"owner": {Name: "Alice", Age: 30}}
+
+	if err := yaml.WriteFileIndent(data, path, 2, 0o644); err != nil {
+		t.Fatalf("WriteFileIndent() unexpected error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "owner:\n  name: Alice\n  age: 30\n"; string(got) != want {
+		t.Errorf("WriteFileIndent() wrote %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o644 {
+		t.Errorf("File perm = %v, want %v", info.Mode().Perm(), os.FileMode(0o644))
+	}
+
+	errTests := []struct {
+		name    string
+		path    string
+		spaces  int
+		wantErr string
+	}{
+		{"Empty path", "", 2, "path cannot be empty or root"},
+		{"Invalid extension", filepath.Join(tempDir, "a.txt"), 2, "file must have .yaml or .yml extension"},
+		{"Invalid indent", filepath.Join(tempDir, "a.yml"), 12, "indent must be between 2 and 9 spaces"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := yaml.WriteFileIndent(data, tt.path, tt.spaces)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WriteFileIndent() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateFile(t *testing.T) {
+	const config = `# Service configuration
+name: api # inline comment
+server:
+  # Listen settings
+  host: localhost
+  port: 8080
+tags:
+  - a
+  - b
+zeta: last
+`
+	setPort := func(port string) func(*yaml.Node) error {
+		return func(doc *yaml.Node) error {
+			server := doc.Content[0].Content[3]
+			server.Content[3].Value = port
+			return nil
+		}
+	}
+
+	t.Run("Preserves comments ordering and indentation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(config), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := yaml.UpdateFile(path, setPort("9090")); err != nil {
+			t.Fatalf("UpdateFile() unexpected error = %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if want := strings.Replace(config, "8080", "9090", 1); string(got) != want {
+			t.Errorf("UpdateFile() content = %q, want %q", got, want)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+			t.Errorf("File perm = %v, want %v", info.Mode().Perm(), os.FileMode(0o640))
+		}
+	})
+
+	t.Run("Keeps four-space indentation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		content := "server:\n    port: 8080 # default\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		err := yaml.UpdateFile(path, func(doc *yaml.Node) error {
+			doc.Content[0].Content[1].Content[1].Value = "9090"
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateFile() unexpected error = %v", err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "server:\n    port: 9090 # default\n" {
+			t.Errorf("UpdateFile() content = %q", got)
+		}
+	})
+
+	t.Run("Bare relative filename", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		if err := os.WriteFile("config.yaml", []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := yaml.UpdateFile("config.yaml", setPort("9090")); err != nil {
+			t.Fatalf("UpdateFile() unexpected error = %v", err)
+		}
+		if got, _ := os.ReadFile("config.yaml"); string(got) != strings.Replace(config, "8080", "9090", 1) {
+			t.Errorf("UpdateFile() content = %q", got)
+		}
+	})
+
+	t.Run("Widens one-space indentation to two", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("server:\n port: 8080\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := yaml.UpdateFile(path, func(*yaml.Node) error { return nil }); err != nil {
+			t.Fatalf("UpdateFile() unexpected error = %v", err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "server:\n  port: 8080\n" {
+			t.Errorf("UpdateFile() content = %q", got)
+		}
+	})
+
+	t.Run("Mutate error leaves file untouched", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		err := yaml.UpdateFile(path, func(*yaml.Node) error { return errors.New("key not found") })
+		if err == nil || err.Error() != "key not found" {
+			t.Errorf("UpdateFile() error = %v, want %q", err, "key not found")
+		}
+		if got, _ := os.ReadFile(path); string(got) != config {
+			t.Errorf("UpdateFile() modified file on error: %q", got)
+		}
+	})
+
+	tempDir := t.TempDir()
+	writeTemp := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name    string
+		path    string
+		mutate  func(*yaml.Node) error
+		wantErr string
+	}{
+		{"Empty path", "", setPort("1"), "path cannot be empty or root"},
+		{"Nil mutate", writeTemp("nil.yaml", config), nil, "mutate function cannot be nil"},
+		{"Missing file", filepath.Join(tempDir, "missing.yaml"), setPort("1"), "file does not exist"},
+		{"Invalid extension", writeTemp("config.txt", config), setPort("1"), "file must have .yaml or .yml extension"},
+		{"Empty file", writeTemp("empty.yaml", ""), setPort("1"), "file is empty"},
+		{"Invalid YAML", writeTemp("invalid.yaml", "name: [unclosed\n"), setPort("1"), "did not find expected"},
+		{"Multiple documents", writeTemp("multi.yaml", "a: 1\n---\nb: 2\n"), setPort("1"), "multi-document YAML files are not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := yaml.UpdateFile(tt.path, tt.mutate)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("UpdateFile() error = %v, wantErr containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}